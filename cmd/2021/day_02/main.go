@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/years/2021/day02"
+)
+
+func main() {
+	aoc.Main(day02.Run)
+}
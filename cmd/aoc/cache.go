@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/cache"
+	"github.com/dugword/advent-of-code-2021/internal/config"
+)
+
+const cacheUsage = `Usage: aoc cache <command> [flags]
+
+Commands:
+  clean  remove every cached answer
+
+Run "aoc cache <command> -h" for the flags of a command.
+`
+
+func manageCache(args []string, stdout, stderr io.Writer) error {
+	if len(args) < 2 {
+		fmt.Fprint(stderr, cacheUsage)
+		return aoc.UsageError(errors.New("must provide a cache command"))
+	}
+	switch args[1] {
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stderr, cacheUsage)
+		return flag.ErrHelp
+	case "clean":
+		return cleanCache(args[1:], stdout, stderr)
+	default:
+		return aoc.UsageError(fmt.Errorf("unknown cache command: %s", args[1]))
+	}
+}
+
+// cleanCache removes every cached answer, so that the next run solves every
+// part again.
+func cleanCache(args []string, stdout, stderr io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return aoc.UsageError(err)
+	}
+	flags := flag.NewFlagSet("cache clean", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	cfg.RegisterCacheDir(flags)
+	if err := aoc.ParseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+	if cfg.CacheDir == "" {
+		return aoc.UsageError(errors.New("must provide a cache directory"))
+	}
+	removed, err := cache.Clean(cfg.CacheDir)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(stdout, "removed %d cached answers from %s\n", removed, cfg.CacheDir)
+	return err
+}
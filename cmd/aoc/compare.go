@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/config"
+	"github.com/dugword/advent-of-code-2021/internal/external"
+	"github.com/dugword/advent-of-code-2021/internal/inputs"
+)
+
+func compare(args []string, stdout, stderr io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return aoc.UsageError(err)
+	}
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	inputFilepath := flags.String("input", "", "path to input file, defaults to the day's input")
+	timeout := flags.Duration("timeout", 0, "stop each solve of a part, Go or external, after this long, 0 for no limit")
+	cfg.RegisterYear(flags)
+	if err := aoc.ParseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return aoc.UsageError(err)
+	}
+	if flags.NArg() < 2 {
+		return aoc.UsageError(errors.New("must provide a day and an external solver command"))
+	}
+	day, err := strconv.Atoi(flags.Arg(0))
+	if err != nil {
+		return aoc.UsageError(fmt.Errorf("invalid day: %w", err))
+	}
+	solver, ok := aoc.Lookup(cfg.Year, day)
+	if !ok {
+		return aoc.UsageError(fmt.Errorf("no solver for day %d of %d", day, cfg.Year))
+	}
+	if *inputFilepath == "" {
+		*inputFilepath = cfg.InputFilepath(cfg.Year, day)
+	}
+	input, err := inputs.ReadFile(*inputFilepath)
+	if err != nil {
+		return aoc.InputError(fmt.Errorf("invalid input file: %w", err))
+	}
+	ctx, cancel := aoc.NotifyContext(0)
+	defer cancel()
+	comparisons := external.Compare(ctx, solver, external.Command(flags.Args()[1:]), input, *timeout)
+	tw := tabwriter.NewWriter(stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "PART\tGO\tEXTERNAL\tMATCH\tGO TIME\tEXTERNAL TIME")
+	mismatches := 0
+	for _, comparison := range comparisons {
+		if !comparison.Match() {
+			mismatches++
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%t\t%s\t%s\n",
+			comparison.Part,
+			describeOutcome(comparison.Go),
+			describeOutcome(comparison.External),
+			comparison.Match(),
+			comparison.Go.Duration.Round(time.Microsecond),
+			comparison.External.Duration.Round(time.Microsecond),
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if mismatches > 0 {
+		return aoc.SolverError(fmt.Errorf("%d of %d parts did not match", mismatches, len(comparisons)))
+	}
+	return nil
+}
+
+func describeOutcome(outcome external.Outcome) string {
+	if outcome.Err != nil {
+		return "error: " + outcome.Err.Error()
+	}
+	return strconv.Itoa(outcome.Answer)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/config"
+)
+
+const configUsage = `Usage: aoc config <command> [flags]
+
+Commands:
+  show  show the settings and where each comes from
+
+Run "aoc config <command> -h" for the flags of a command.
+`
+
+func configure(args []string, stdout, stderr io.Writer) error {
+	if len(args) < 2 {
+		fmt.Fprint(stderr, configUsage)
+		return aoc.UsageError(errors.New("must provide a config command"))
+	}
+	switch args[1] {
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stderr, configUsage)
+		return flag.ErrHelp
+	case "show":
+		return showConfig(args[1:], stdout, stderr)
+	default:
+		return aoc.UsageError(fmt.Errorf("unknown config command: %s", args[1]))
+	}
+}
+
+// showConfig writes the settings as the run command would see them with the
+// same flags.
+func showConfig(args []string, stdout, stderr io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return aoc.UsageError(err)
+	}
+	flags := flag.NewFlagSet("config show", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	cfg.Register(flags)
+	if err := aoc.ParseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return aoc.UsageError(err)
+	}
+	return cfg.WriteTable(stdout)
+}
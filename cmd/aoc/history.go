@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/config"
+	"github.com/dugword/advent-of-code-2021/internal/history"
+)
+
+// showHistory writes the trend of each part of a year recorded in the history
+// file, or every build of each part of one day.
+func showHistory(args []string, stdout, stderr io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return aoc.UsageError(err)
+	}
+	flags := flag.NewFlagSet("history", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	cfg.RegisterYear(flags)
+	cfg.RegisterHistoryFile(flags)
+	day := flags.Int("day", 0, "show every build of each part of this `day`")
+	if err := aoc.ParseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return aoc.UsageError(err)
+	}
+	if cfg.HistoryFile == "" {
+		return aoc.UsageError(errors.New("must provide a history file"))
+	}
+	records, err := history.Load(cfg.HistoryFile)
+	if err != nil {
+		return aoc.InputError(err)
+	}
+	var selected []history.Record
+	for _, record := range records {
+		if record.Year == cfg.Year && (*day == 0 || record.Day == *day) {
+			selected = append(selected, record)
+		}
+	}
+	if len(selected) == 0 {
+		if *day != 0 {
+			_, err := fmt.Fprintf(stdout, "no history for day %d of %d\n", *day, cfg.Year)
+			return err
+		}
+		_, err := fmt.Fprintf(stdout, "no history for %d\n", cfg.Year)
+		return err
+	}
+	trends := history.Trends(selected)
+	if *day != 0 {
+		return history.WriteBuilds(stdout, trends)
+	}
+	return history.WriteTrends(stdout, trends)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/config"
+	"github.com/dugword/advent-of-code-2021/internal/inputs"
+)
+
+const inputsUsage = `Usage: aoc inputs <command> [flags] [files]
+
+Commands:
+  encrypt  encrypt the inputs of a year, or the files given, with AOC_INPUT_KEY
+  decrypt  decrypt the inputs of a year, or the files given, with AOC_INPUT_KEY
+  key      make a new key for AOC_INPUT_KEY
+
+Run "aoc inputs <command> -h" for the flags of a command.
+`
+
+func manageInputs(args []string, stdout, stderr io.Writer) error {
+	if len(args) < 2 {
+		fmt.Fprint(stderr, inputsUsage)
+		return aoc.UsageError(errors.New("must provide an inputs command"))
+	}
+	switch args[1] {
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stderr, inputsUsage)
+		return flag.ErrHelp
+	case "encrypt":
+		return cryptInputs(args[1:], stdout, stderr, inputs.EncryptFile)
+	case "decrypt":
+		return cryptInputs(args[1:], stdout, stderr, inputs.DecryptFile)
+	case "key":
+		return makeKey(args[1:], stdout, stderr)
+	default:
+		return aoc.UsageError(fmt.Errorf("unknown inputs command: %s", args[1]))
+	}
+}
+
+// makeKey writes a new key for encrypting the inputs.
+func makeKey(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("inputs key", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	if err := aoc.ParseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+	key, err := inputs.NewKey()
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, key)
+	return err
+}
+
+// cryptInputs encrypts or decrypts the input files given as arguments, or
+// else the input of every day of the year that exists in the form being
+// converted from.
+func cryptInputs(args []string, stdout, stderr io.Writer, crypt func(key []byte, path string) error) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return aoc.UsageError(err)
+	}
+	flags := flag.NewFlagSet("inputs "+args[0], flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	cfg.RegisterYear(flags)
+	cfg.RegisterInputDir(flags)
+	if err := aoc.ParseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return aoc.UsageError(err)
+	}
+	key, err := inputs.KeyFromEnv()
+	if err != nil {
+		return aoc.UsageError(err)
+	}
+	encrypt := args[0] == "encrypt"
+	paths := flags.Args()
+	if len(paths) == 0 {
+		for _, solver := range aoc.YearSolvers(cfg.Year) {
+			path := cfg.InputFilepath(solver.Year, solver.Day)
+			from := path
+			if !encrypt {
+				from += inputs.EncryptedExt
+			}
+			if _, err := os.Stat(from); errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return aoc.InputError(fmt.Errorf("no inputs to %s for %d", args[0], cfg.Year))
+	}
+	for _, path := range paths {
+		if err := crypt(key, path); err != nil {
+			return aoc.InputError(err)
+		}
+		if encrypt {
+			fmt.Fprintf(stdout, "encrypted %s to %s%s\n", path, path, inputs.EncryptedExt)
+		} else {
+			fmt.Fprintf(stdout, "decrypted %s%s to %s\n", path, inputs.EncryptedExt, path)
+		}
+	}
+	return nil
+}
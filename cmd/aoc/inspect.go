@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/inputs"
+	"github.com/dugword/advent-of-code-2021/internal/inspect"
+)
+
+// inspectInput reports the shape of an input file and suggests a loader for
+// it, to start a new day from an unfamiliar input.
+func inspectInput(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	if err := aoc.ParseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return aoc.UsageError(errors.New("must provide an input file"))
+	}
+	input, err := inputs.ReadFile(flags.Arg(0))
+	if err != nil {
+		return aoc.InputError(fmt.Errorf("invalid input file: %w", err))
+	}
+	report, err := inspect.Inspect(bytes.NewReader(input))
+	if err != nil {
+		return aoc.InputError(fmt.Errorf("invalid input file: %w", err))
+	}
+	return report.Write(stdout)
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/cache"
+	"github.com/dugword/advent-of-code-2021/internal/config"
+	"github.com/dugword/advent-of-code-2021/internal/history"
+	"github.com/dugword/advent-of-code-2021/internal/logging"
+	_ "github.com/dugword/advent-of-code-2021/years"
+)
+
+const usage = `Usage: aoc <command> [arguments]
+
+Commands:
+  run      solve every registered day
+  gen      generate a random input for a day
+  watch    rerun a day whenever it changes
+  serve    serve the solvers over HTTP
+  compare  compare an external solver with the Go solver
+  config   show the settings and where each comes from
+  inputs   encrypt or decrypt the puzzle inputs, or make a key
+  history  show how the answers and durations of each part changed
+  cache    clean the cache of answers
+  inspect  describe an input file and suggest a loader for it
+
+Run "aoc <command> -h" for the flags of a command.
+`
+
+func main() {
+	aoc.Main(Run)
+}
+
+// Run is an abstraction for main() that enables testing.
+func Run(args []string, stdout, stderr io.Writer) error {
+	if len(args) < 2 {
+		fmt.Fprint(stderr, usage)
+		return aoc.UsageError(errors.New("must provide a command"))
+	}
+	switch args[1] {
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stderr, usage)
+		return flag.ErrHelp
+	case "run":
+		return runAll(args[1:], stdout, stderr)
+	case "gen":
+		return generate(args[1:], stdout, stderr)
+	case "watch":
+		return watchDay(args[1:], stdout, stderr)
+	case "serve":
+		return serve(args[1:], stdout, stderr)
+	case "compare":
+		return compare(args[1:], stdout, stderr)
+	case "config":
+		return configure(args[1:], stdout, stderr)
+	case "inputs":
+		return manageInputs(args[1:], stdout, stderr)
+	case "history":
+		return showHistory(args[1:], stdout, stderr)
+	case "cache":
+		return manageCache(args[1:], stdout, stderr)
+	case "inspect":
+		return inspectInput(args[1:], stdout, stderr)
+	default:
+		return aoc.UsageError(fmt.Errorf("unknown command: %s", args[1]))
+	}
+}
+
+func runAll(args []string, stdout, stderr io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return aoc.UsageError(err)
+	}
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	cfg.Register(flags)
+	noCache := flags.Bool("no-cache", false, "solve every part instead of using cached answers")
+	options := aoc.Options{JSON: cfg.Format == config.FormatJSON}
+	options.Register(flags)
+	if err := aoc.ParseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return aoc.UsageError(err)
+	}
+	solvers := aoc.YearSolvers(cfg.Year)
+	if len(solvers) == 0 {
+		return aoc.UsageError(fmt.Errorf("no solvers for %d", cfg.Year))
+	}
+	runner := aoc.Runner{
+		InputFilepath: cfg.InputFilepath,
+		Workers:       cfg.Workers,
+		Timeout:       options.Timeout,
+		CheckExamples: options.CheckExample,
+	}
+	ctx, cancel := aoc.NotifyContext(0)
+	defer cancel()
+	logger := options.Logger(stderr)
+	ctx = logging.NewContext(ctx, logger)
+	if !*noCache && cfg.CacheDir != "" {
+		buildID, err := cache.BuildID()
+		if err != nil {
+			logger.WarnContext(ctx, "not caching answers", "error", err)
+		} else {
+			runner.Cache = cache.New(cfg.CacheDir, buildID)
+		}
+	}
+	start := time.Now()
+	var report aoc.Report
+	err = options.Profile(stderr, func() error {
+		report = runner.RunAll(ctx, solvers)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	write := report.WriteSummary
+	if options.JSON {
+		write = report.WriteJSON
+	}
+	if err := write(stdout); err != nil {
+		return err
+	}
+	for _, result := range report.Results {
+		if errors.Is(result.Err, aoc.ErrTimeout) {
+			fmt.Fprintf(stderr, "%d day %d part %d timed out after %s\n", result.Year, result.Day, result.Part, options.Timeout)
+		}
+	}
+	if cfg.SolutionsDir != "" {
+		if err := writeSolutions(cfg, report); err != nil {
+			return err
+		}
+	}
+	if cfg.HistoryFile != "" {
+		if err := history.Append(cfg.HistoryFile, history.Records(report, start, history.Commit())); err != nil {
+			return err
+		}
+	}
+	if failures := report.Failures(); failures > 0 {
+		return aoc.SolverError(fmt.Errorf("%d of %d parts failed", failures, len(report.Results)))
+	}
+	return nil
+}
+
+// writeSolutions writes each answer to a file in the solutions directory of
+// its year.
+func writeSolutions(cfg config.Config, report aoc.Report) error {
+	for _, result := range report.Results {
+		if result.Err != nil {
+			continue
+		}
+		dir := cfg.YearSolutionsDir(result.Year)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		name := fmt.Sprintf("day_%02d_part_%d", result.Day, result.Part)
+		contents := fmt.Sprintf("%d\n", result.Answer)
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generate an input with the generator of a day. The flags of the generator
+// depend on the day, so the year is parsed before the day and the other flags
+// after it: gen [-year year] day [flags].
+func generate(args []string, stdout, stderr io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return aoc.UsageError(err)
+	}
+	yearFlags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	yearFlags.SetOutput(stderr)
+	cfg.RegisterYear(yearFlags)
+	if err := aoc.ParseFlags(yearFlags, args[1:]); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return aoc.UsageError(err)
+	}
+	if yearFlags.NArg() < 1 {
+		return aoc.UsageError(errors.New("must provide a day"))
+	}
+	day, err := strconv.Atoi(yearFlags.Arg(0))
+	if err != nil {
+		return aoc.UsageError(fmt.Errorf("invalid day: %w", err))
+	}
+	solver, ok := aoc.Lookup(cfg.Year, day)
+	if !ok || solver.NewGenerator == nil {
+		return aoc.UsageError(fmt.Errorf("no generator for day %d of %d", day, cfg.Year))
+	}
+	generator := solver.NewGenerator()
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	seed := flags.Int64("seed", 1, "seed for the random number generator")
+	showAnswers := flags.Bool("answers", false, "write the answers to stderr")
+	generator.Register(flags)
+	if err := aoc.ParseFlags(flags, yearFlags.Args()[1:]); err != nil {
+		return err
+	}
+	answers, err := generator.Generate(stdout, rand.New(rand.NewSource(*seed)))
+	if err != nil {
+		return err
+	}
+	if *showAnswers {
+		fmt.Fprintf(stderr, "part 1: %d\npart 2: %d\n", answers[0], answers[1])
+	}
+	return nil
+}
@@ -0,0 +1,606 @@
+package main_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	main "github.com/dugword/advent-of-code-2021/cmd/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/aoctest"
+	"github.com/dugword/advent-of-code-2021/internal/history"
+)
+
+// TestMain keeps the settings of the developer, from AOC_* variables and the
+// config file in their user config directory, out of the tests. It also keeps
+// the run command from recording a history in the source tree and from
+// answering from the cache of the user.
+func TestMain(m *testing.M) {
+	for _, env := range os.Environ() {
+		if name, _, _ := strings.Cut(env, "="); strings.HasPrefix(name, "AOC_") {
+			os.Unsetenv(name)
+		}
+	}
+	home, err := os.MkdirTemp("", "aoc-test-home")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	// os.UserConfigDir and os.UserCacheDir look in these on each platform.
+	for _, name := range []string{"HOME", "XDG_CONFIG_HOME", "XDG_CACHE_HOME", "AppData", "LocalAppData"} {
+		os.Setenv(name, home)
+	}
+	os.Setenv("AOC_HISTORY_FILE", "")
+	os.Setenv("AOC_CACHE_DIR", "")
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
+
+func TestRun(t *testing.T) {
+	t.Run("run all days without failure", func(t *testing.T) {
+		solutionsDir := t.TempDir()
+		historyFile := filepath.Join(t.TempDir(), "history.jsonl")
+		args := []string{
+			"aoc", "run",
+			"-inputs", "./testdata/inputs",
+			"-solutions", solutionsDir,
+			"-history", historyFile,
+		}
+		got := aoctest.Run(t, main.Run, args...)
+		if !strings.Contains(got, "total wall:") {
+			t.Errorf("missing summary in %q", got)
+		}
+		if _, err := os.Stat(filepath.Join(solutionsDir, "2021", "day_01_part_1")); err != nil {
+			t.Error(err)
+		}
+		records, err := history.Load(historyFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 6 || records[0].Answer != 2 || records[0].InputHash == "" {
+			t.Errorf("got history: %+v", records)
+		}
+	})
+	t.Run("print usage on help", func(t *testing.T) {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		got := main.Run([]string{"aoc", "-h"}, &stdout, &stderr)
+		aoctest.AssertExitCode(t, got, aoc.ExitOK)
+		if !strings.HasPrefix(stderr.String(), "Usage: aoc") {
+			t.Errorf("missing usage in %q", stderr.String())
+		}
+	})
+	t.Run("print usage of a command on help", func(t *testing.T) {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		got := main.Run([]string{"aoc", "run", "-h"}, &stdout, &stderr)
+		aoctest.AssertExitCode(t, got, aoc.ExitOK)
+		if !strings.Contains(stderr.String(), "-workers") {
+			t.Errorf("missing usage in %q", stderr.String())
+		}
+	})
+	t.Run("fail on missing command", func(t *testing.T) {
+		got := aoctest.RunError(t, main.Run, "aoc")
+		aoctest.AssertError(t, got, "must provide a command")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+	t.Run("fail on unknown command", func(t *testing.T) {
+		got := aoctest.RunError(t, main.Run, "aoc", "invalid")
+		aoctest.AssertError(t, got, "unknown command: invalid")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+	t.Run("fail on year without solvers", func(t *testing.T) {
+		got := aoctest.RunError(t, main.Run, "aoc", "run", "-year", "2015")
+		aoctest.AssertError(t, got, "no solvers for 2015")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+	t.Run("fail on missing inputs", func(t *testing.T) {
+		got := aoctest.RunError(t, main.Run, "aoc", "run", "-inputs", "./testdata/missing")
+		aoctest.AssertError(t, got, "6 of 6 parts failed")
+		aoctest.AssertExitCode(t, got, aoc.ExitSolver)
+	})
+}
+
+func TestHelp(t *testing.T) {
+	commands := [][]string{
+		{"run"},
+		{"gen"},
+		{"gen", "1"},
+		{"watch"},
+		{"serve"},
+		{"compare"},
+		{"config"},
+		{"config", "show"},
+		{"inputs"},
+		{"inputs", "encrypt"},
+		{"inputs", "decrypt"},
+		{"inputs", "key"},
+		{"history"},
+		{"cache"},
+		{"cache", "clean"},
+		{"inspect"},
+	}
+	for _, command := range commands {
+		for _, help := range []string{"-h", "-help"} {
+			args := append(append([]string{"aoc"}, command...), help)
+			t.Run(strings.Join(args[1:], " "), func(t *testing.T) {
+				got := aoctest.RunError(t, main.Run, args...)
+				aoctest.AssertExitCode(t, got, aoc.ExitOK)
+			})
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("generate an input with answers", func(t *testing.T) {
+		args := []string{
+			"aoc", "gen", "1",
+			"-seed", "3",
+			"-n", "10",
+			"-answers",
+		}
+		stdout, stderr := aoctest.RunStderr(t, main.Run, args...)
+		if got := strings.Count(stdout, "\n"); got != 10 {
+			t.Errorf("got: %d lines, want: 10", got)
+		}
+		if !strings.HasPrefix(stderr, "part 1: ") {
+			t.Errorf("missing answers in %q", stderr)
+		}
+	})
+	t.Run("generate an input of a year", func(t *testing.T) {
+		stdout := aoctest.Run(t, main.Run, "aoc", "gen", "-year", "2021", "2", "-n", "5")
+		if got := strings.Count(stdout, "\n"); got != 5 {
+			t.Errorf("got: %d lines, want: 5", got)
+		}
+	})
+	t.Run("fail on unknown year", func(t *testing.T) {
+		got := aoctest.RunError(t, main.Run, "aoc", "gen", "-year", "2015", "1")
+		aoctest.AssertError(t, got, "no generator for day 1 of 2015")
+	})
+	t.Run("fail on missing day", func(t *testing.T) {
+		got := aoctest.RunError(t, main.Run, "aoc", "gen")
+		aoctest.AssertError(t, got, "must provide a day")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+	t.Run("fail on unknown day", func(t *testing.T) {
+		got := aoctest.RunError(t, main.Run, "aoc", "gen", "99")
+		aoctest.AssertError(t, got, "no generator for day 99 of 2021")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+}
+
+func TestWatch(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+		want string
+		code int
+	}{
+		{
+			name: "fail on missing day",
+			args: []string{"aoc", "watch"},
+			want: "must provide a day",
+			code: aoc.ExitUsage,
+		},
+		{
+			name: "fail on invalid day",
+			args: []string{"aoc", "watch", "one"},
+			want: `invalid day: strconv.Atoi: parsing "one": invalid syntax`,
+			code: aoc.ExitUsage,
+		},
+		{
+			name: "fail on invalid interval",
+			args: []string{"aoc", "watch", "1", "-interval", "0s"},
+			want: "interval must be positive",
+			code: aoc.ExitUsage,
+		},
+		{
+			name: "fail on invalid interval before the day",
+			args: []string{"aoc", "watch", "-interval", "0s", "1"},
+			want: "interval must be positive",
+			code: aoc.ExitUsage,
+		},
+		{
+			name: "fail on missing package",
+			args: []string{"aoc", "watch", "99", "-years", "../../years"},
+			want: "no files to watch in ../../years/2021/day99",
+			code: aoc.ExitUsage,
+		},
+		{
+			name: "fail on missing package with an input",
+			args: []string{"aoc", "watch", "99", "-years", "../../years", "-inputs", "testdata/watch"},
+			want: "no files to watch in ../../years/2021/day99",
+			code: aoc.ExitUsage,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			got := aoctest.RunError(t, main.Run, testCase.args...)
+			aoctest.AssertError(t, got, testCase.want)
+			aoctest.AssertExitCode(t, got, testCase.code)
+		})
+	}
+}
+
+func TestServe(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+		want string
+		code int
+	}{
+		{
+			name: "fail on invalid max body",
+			args: []string{"aoc", "serve", "-max-body", "0"},
+			want: "max body must be positive",
+			code: aoc.ExitUsage,
+		},
+		{
+			name: "fail on invalid timeout",
+			args: []string{"aoc", "serve", "-timeout", "0s"},
+			want: "timeout must be positive",
+			code: aoc.ExitUsage,
+		},
+		{
+			name: "fail on invalid address",
+			args: []string{"aoc", "serve", "-addr", "invalid:address:0"},
+			want: "listen tcp: address invalid:address:0: too many colons in address",
+			code: aoc.ExitFailure,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			got := aoctest.RunError(t, main.Run, testCase.args...)
+			aoctest.AssertError(t, got, testCase.want)
+			aoctest.AssertExitCode(t, got, testCase.code)
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+		want string
+		code int
+	}{
+		{
+			name: "fail on missing command",
+			args: []string{"aoc", "compare", "1"},
+			want: "must provide a day and an external solver command",
+			code: aoc.ExitUsage,
+		},
+		{
+			name: "fail on unknown day",
+			args: []string{"aoc", "compare", "99", "solver"},
+			want: "no solver for day 99 of 2021",
+			code: aoc.ExitUsage,
+		},
+		{
+			name: "fail on missing input",
+			args: []string{"aoc", "compare", "-input", "./testdata/missing", "1", "solver"},
+			want: "invalid input file: open ./testdata/missing: no such file or directory",
+			code: aoc.ExitInput,
+		},
+		{
+			name: "fail on failing external solver",
+			args: []string{"aoc", "compare", "-input", "../../years/2021/day01/testdata/input", "1", "./testdata/missing"},
+			want: "2 of 2 parts did not match",
+			code: aoc.ExitSolver,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			got := aoctest.RunError(t, main.Run, testCase.args...)
+			aoctest.AssertError(t, got, testCase.want)
+			aoctest.AssertExitCode(t, got, testCase.code)
+		})
+	}
+}
+
+func TestConfig(t *testing.T) {
+	t.Run("show settings with their sources", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(path, []byte(`{"input_dir": "/inputs"}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("AOC_CONFIG", path)
+		t.Setenv("AOC_WORKERS", "3")
+		got := aoctest.Run(t, main.Run, "aoc", "config", "show", "-solutions", "/solutions")
+		for _, want := range []string{
+			"config file: " + path,
+			"input_dir      /inputs",
+			"solutions_dir  /solutions",
+			"workers        3",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("missing %q in %q", want, got)
+			}
+		}
+	})
+	testCases := []struct {
+		name string
+		args []string
+		want string
+		code int
+	}{
+		{
+			name: "fail on missing config command",
+			args: []string{"aoc", "config"},
+			want: "must provide a config command",
+			code: aoc.ExitUsage,
+		},
+		{
+			name: "fail on unknown config command",
+			args: []string{"aoc", "config", "invalid"},
+			want: "unknown config command: invalid",
+			code: aoc.ExitUsage,
+		},
+		{
+			name: "fail on invalid workers",
+			args: []string{"aoc", "config", "show", "-workers", "0"},
+			want: "workers must be positive",
+			code: aoc.ExitUsage,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			got := aoctest.RunError(t, main.Run, testCase.args...)
+			aoctest.AssertError(t, got, testCase.want)
+			aoctest.AssertExitCode(t, got, testCase.code)
+		})
+	}
+}
+
+func TestInputs(t *testing.T) {
+	key := strings.TrimSpace(aoctest.Run(t, main.Run, "aoc", "inputs", "key"))
+	inputDir := t.TempDir()
+	dayDir := filepath.Join(inputDir, "2021", "day01")
+	if err := os.MkdirAll(dayDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	input, err := os.ReadFile("./testdata/inputs/2021/day01/input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputFilepath := filepath.Join(dayDir, "input")
+	if err := os.WriteFile(inputFilepath, input, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("encrypt inputs and solve them", func(t *testing.T) {
+		t.Setenv("AOC_INPUT_KEY", key)
+		aoctest.Run(t, main.Run, "aoc", "inputs", "encrypt", "-inputs", inputDir)
+		if err := os.Remove(inputFilepath); err != nil {
+			t.Fatal(err)
+		}
+		// Only day 1 has an input, so the run fails for the other days.
+		var stdout bytes.Buffer
+		main.Run([]string{"aoc", "run", "-json", "-inputs", inputDir}, &stdout, io.Discard)
+		want := `{"year":2021,"day":1,"part":1,"answer":2}`
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("missing %q in %q", want, stdout.String())
+		}
+		aoctest.Run(t, main.Run, "aoc", "inputs", "decrypt", "-inputs", inputDir)
+		got, err := os.ReadFile(inputFilepath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, input) {
+			t.Errorf("decrypted input differs from the original")
+		}
+	})
+	testCases := []struct {
+		name string
+		args []string
+		key  string
+		want string
+		code int
+	}{
+		{
+			name: "fail on missing inputs command",
+			args: []string{"aoc", "inputs"},
+			want: "must provide an inputs command",
+			code: aoc.ExitUsage,
+		},
+		{
+			name: "fail on unknown inputs command",
+			args: []string{"aoc", "inputs", "invalid"},
+			want: "unknown inputs command: invalid",
+			code: aoc.ExitUsage,
+		},
+		{
+			name: "fail on missing key",
+			args: []string{"aoc", "inputs", "encrypt", "-inputs", inputDir},
+			want: "AOC_INPUT_KEY is not set",
+			code: aoc.ExitUsage,
+		},
+		{
+			name: "fail on wrong key",
+			args: []string{"aoc", "inputs", "decrypt", "-inputs", inputDir},
+			key:  strings.Repeat("00", 32),
+			want: inputFilepath + ".enc: wrong key or tampered input",
+			code: aoc.ExitInput,
+		},
+		{
+			name: "fail on no inputs",
+			args: []string{"aoc", "inputs", "decrypt", "-inputs", t.TempDir()},
+			key:  key,
+			want: "no inputs to decrypt for 2021",
+			code: aoc.ExitInput,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Setenv("AOC_INPUT_KEY", testCase.key)
+			got := aoctest.RunError(t, main.Run, testCase.args...)
+			aoctest.AssertError(t, got, testCase.want)
+			aoctest.AssertExitCode(t, got, testCase.code)
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+	historyFile := filepath.Join(t.TempDir(), "history.jsonl")
+	start := time.Date(2021, 12, 1, 5, 0, 0, 0, time.UTC)
+	records := []history.Record{
+		{Time: start, Commit: "c1", Year: 2021, Day: 1, Part: 1, Answer: 7, Duration: time.Millisecond},
+		{Time: start.Add(time.Hour), Commit: "c2", Year: 2021, Day: 1, Part: 1, Answer: 8, Duration: 2 * time.Millisecond},
+		{Time: start, Commit: "c1", Year: 2022, Day: 1, Part: 1, Answer: 9},
+	}
+	if err := history.Append(historyFile, records); err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "show the trends of a year",
+			args: []string{"aoc", "history", "-history", historyFile},
+			want: "YEAR  DAY  PART  RUNS  BUILDS  ANSWER  MEDIAN  ALLOCS  TREND    CHANGES\n" +
+				"2021  1    1     2     2       8       2ms     -       +100.0%  1\n" +
+				"2021 day 1 part 1 changed from 7 to 8 at 2021-12-01T06:00:00Z in c2\n",
+		},
+		{
+			name: "show the builds of a day",
+			args: []string{"aoc", "history", "-history", historyFile, "-year", "2022", "-day", "1"},
+			want: "YEAR  DAY  PART  COMMIT  FIRST RUN             RUNS  ANSWER  MEDIAN  ALLOCS\n" +
+				"2022  1    1     c1      2021-12-01T05:00:00Z  1     9       0s      -\n",
+		},
+		{
+			name: "show no history of a day",
+			args: []string{"aoc", "history", "-history", historyFile, "-day", "2"},
+			want: "no history for day 2 of 2021\n",
+		},
+		{
+			name: "show no history of a missing file",
+			args: []string{"aoc", "history", "-history", filepath.Join(t.TempDir(), "missing")},
+			want: "no history for 2021\n",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := aoctest.Run(t, main.Run, testCase.args...); got != testCase.want {
+				t.Errorf("got: %q, want: %q", got, testCase.want)
+			}
+		})
+	}
+	t.Run("fail without a history file", func(t *testing.T) {
+		got := aoctest.RunError(t, main.Run, "aoc", "history", "-history", "")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+}
+
+func TestCache(t *testing.T) {
+	cacheDir := filepath.Join(t.TempDir(), "answers")
+	run := func(t *testing.T, args ...string) string {
+		t.Helper()
+		args = append([]string{"aoc", "run", "-inputs", "./testdata/inputs", "-cache", cacheDir, "-json"}, args...)
+		return aoctest.Run(t, main.Run, args...)
+	}
+	const cached = `{"year":2021,"day":1,"part":1,"answer":2,"cached":true}`
+	t.Run("solve every part the first time", func(t *testing.T) {
+		if got := run(t); strings.Contains(got, `"cached"`) {
+			t.Errorf("got: %q, want nothing cached", got)
+		}
+	})
+	t.Run("answer from the cache the second time", func(t *testing.T) {
+		if got := run(t); !strings.Contains(got, cached) {
+			t.Errorf("got: %q, want to contain: %q", got, cached)
+		}
+	})
+	t.Run("solve every part without the cache", func(t *testing.T) {
+		if got := run(t, "-no-cache"); strings.Contains(got, `"cached"`) {
+			t.Errorf("got: %q, want nothing cached", got)
+		}
+	})
+	t.Run("clean the cache", func(t *testing.T) {
+		want := "removed 6 cached answers from " + cacheDir + "\n"
+		if got := aoctest.Run(t, main.Run, "aoc", "cache", "clean", "-cache", cacheDir); got != want {
+			t.Errorf("got: %q, want: %q", got, want)
+		}
+		if got := run(t); strings.Contains(got, `"cached"`) {
+			t.Errorf("got: %q, want nothing cached", got)
+		}
+	})
+	testCases := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "fail on missing cache command",
+			args: []string{"aoc", "cache"},
+			want: "must provide a cache command",
+		},
+		{
+			name: "fail on unknown cache command",
+			args: []string{"aoc", "cache", "invalid"},
+			want: "unknown cache command: invalid",
+		},
+		{
+			name: "fail without a cache directory",
+			args: []string{"aoc", "cache", "clean"},
+			want: "must provide a cache directory",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			got := aoctest.RunError(t, main.Run, testCase.args...)
+			aoctest.AssertError(t, got, testCase.want)
+			aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+		})
+	}
+}
+
+func TestInspect(t *testing.T) {
+	t.Run("inspect an input", func(t *testing.T) {
+		got := aoctest.Run(t, main.Run, "aoc", "inspect", "./testdata/inputs/2021/day01/input")
+		for _, want := range []string{"format        ints\n", "loader        inputs.Ints(ctx, r)\n"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("got: %q, want to contain: %q", got, want)
+			}
+		}
+	})
+	testCases := []struct {
+		name string
+		args []string
+		want string
+		code int
+	}{
+		{
+			name: "fail without an input file",
+			args: []string{"aoc", "inspect"},
+			want: "must provide an input file",
+			code: aoc.ExitUsage,
+		},
+		{
+			name: "fail on missing input file",
+			args: []string{"aoc", "inspect", "missing"},
+			want: "invalid input file: open missing: no such file or directory",
+			code: aoc.ExitInput,
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			got := aoctest.RunError(t, main.Run, testCase.args...)
+			aoctest.AssertError(t, got, testCase.want)
+			aoctest.AssertExitCode(t, got, testCase.code)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/config"
+	"github.com/dugword/advent-of-code-2021/internal/server"
+)
+
+// readTimeout limits reading a request, including its puzzle input.
+const readTimeout = 30 * time.Second
+
+func serve(args []string, stdout, stderr io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return aoc.UsageError(err)
+	}
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	cfg.RegisterYear(flags)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	maxBodyBytes := flags.Int64("max-body", server.DefaultConfig.MaxBodyBytes, "largest puzzle input accepted, in bytes")
+	timeout := flags.Duration("timeout", server.DefaultConfig.Timeout, "stop solving a puzzle input after this long")
+	maxSolves := flags.Int("max-solves", server.DefaultConfig.MaxSolves, "most puzzle inputs solved at once, 0 for one per CPU")
+	if err := aoc.ParseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return aoc.UsageError(err)
+	}
+	if *maxBodyBytes <= 0 {
+		return aoc.UsageError(errors.New("max body must be positive"))
+	}
+	if *timeout <= 0 {
+		return aoc.UsageError(errors.New("timeout must be positive"))
+	}
+	if *maxSolves < 0 {
+		return aoc.UsageError(errors.New("max solves must not be negative"))
+	}
+	srv := &http.Server{
+		Addr: *addr,
+		Handler: server.New(server.Config{
+			Year:         cfg.Year,
+			MaxBodyBytes: *maxBodyBytes,
+			Timeout:      *timeout,
+			MaxSolves:    *maxSolves,
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+		// Slow clients cannot hold a connection open while sending an input,
+		// and each response has time to read, solve and write its input.
+		ReadTimeout:  readTimeout,
+		WriteTimeout: readTimeout + *timeout + 10*time.Second,
+	}
+	ctx, cancel := aoc.NotifyContext(0)
+	defer cancel()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- srv.ListenAndServe()
+	}()
+	fmt.Fprintf(stdout, "listening on %s\n", *addr)
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), *timeout)
+	defer shutdownCancel()
+	return srv.Shutdown(shutdownCtx)
+}
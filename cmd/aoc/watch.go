@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/config"
+	"github.com/dugword/advent-of-code-2021/internal/watch"
+)
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// watchDay reruns a day whenever it changes: watch [flags] day [flags].
+func watchDay(args []string, stdout, stderr io.Writer) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return aoc.UsageError(err)
+	}
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	yearsDir := flags.String("years", "./years", "directory containing a YYYY/dayNN package per day")
+	cfg.RegisterYear(flags)
+	cfg.RegisterInputDir(flags)
+	interval := flags.Duration("interval", 500*time.Millisecond, "how often to poll for changes")
+	if err := aoc.ParseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() < 1 {
+		return aoc.UsageError(errors.New("must provide a day"))
+	}
+	day, err := strconv.Atoi(flags.Arg(0))
+	if err != nil {
+		return aoc.UsageError(fmt.Errorf("invalid day: %w", err))
+	}
+	// Flags may follow the day as well as precede it.
+	if err := aoc.ParseFlags(flags, flags.Args()[1:]); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return aoc.UsageError(err)
+	}
+	if *interval <= 0 {
+		return aoc.UsageError(errors.New("interval must be positive"))
+	}
+	packageDir := filepath.Join(*yearsDir, strconv.Itoa(cfg.Year), fmt.Sprintf("day%02d", day))
+	commandDir := filepath.Join("cmd", strconv.Itoa(cfg.Year), fmt.Sprintf("day_%02d", day))
+	inputFilepath := cfg.InputFilepath(cfg.Year, day)
+	watcher := watch.Watcher{
+		// The encrypted input is read when the plain one is missing.
+		Paths:    []string{packageDir, commandDir, inputFilepath, inputFilepath + ".enc"},
+		Interval: *interval,
+	}
+	snapshot, err := watch.Take(watcher.Paths)
+	if err != nil {
+		return err
+	}
+	if !watchesPackage(snapshot, packageDir) {
+		return aoc.UsageError(fmt.Errorf("no files to watch in %s", packageDir))
+	}
+	ctx, cancel := aoc.NotifyContext(0)
+	defer cancel()
+	var previous [2]string
+	for {
+		fmt.Fprint(stdout, clearScreen)
+		fmt.Fprintf(stdout, "%d day %d at %s\n\n", cfg.Year, day, time.Now().Format(time.Kitchen))
+		previous = rerun(ctx, stdout, packageDir, commandDir, inputFilepath, previous)
+		fmt.Fprintf(stdout, "\nwatching %s for changes\n", strings.Join(watcher.Paths, ", "))
+		snapshot, _, err = watcher.Wait(ctx, snapshot)
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// rerun the tests of a day's package and both parts of its command on its
+// input, comparing each answer with the previous one. It returns the new
+// answers.
+func rerun(ctx context.Context, stdout io.Writer, packageDir, commandDir, inputFilepath string, previous [2]string) [2]string {
+	output, err := goCommand(ctx, "test", packagePath(packageDir))
+	if err != nil {
+		fmt.Fprintf(stdout, "tests failed:\n%s\n", output)
+	} else {
+		fmt.Fprintf(stdout, "tests: %s\n", strings.TrimSpace(output))
+	}
+	var answers [2]string
+	for part := 1; part <= 2; part++ {
+		args := []string{"run", packagePath(commandDir), "-input", inputFilepath}
+		if part == 2 {
+			args = append(args, "-part-2")
+		}
+		output, err := goCommand(ctx, args...)
+		if err != nil {
+			answers[part-1] = previous[part-1]
+			fmt.Fprintf(stdout, "part %d failed: %s\n", part, strings.TrimSpace(output))
+			continue
+		}
+		answers[part-1] = strings.TrimSpace(output)
+		fmt.Fprintf(stdout, "part %d: %s\n", part, describeChange(previous[part-1], answers[part-1]))
+	}
+	return answers
+}
+
+// watchesPackage reports whether the snapshot has any file of the package in
+// packageDir.
+func watchesPackage(snapshot watch.Snapshot, packageDir string) bool {
+	prefix := filepath.Clean(packageDir) + string(filepath.Separator)
+	for path := range snapshot {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeChange between the previous and current answer.
+func describeChange(previous, current string) string {
+	switch previous {
+	case "":
+		return current
+	case current:
+		return current + " (unchanged)"
+	default:
+		return fmt.Sprintf("%s (was %s)", current, previous)
+	}
+}
+
+// packagePath makes dir usable as a package path for the go command, which
+// treats paths without a leading dot as import paths.
+func packagePath(dir string) string {
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+	return "./" + filepath.ToSlash(dir)
+}
+
+func goCommand(ctx context.Context, args ...string) (string, error) {
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err := cmd.Run()
+	return output.String(), err
+}
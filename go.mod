@@ -1,3 +1,3 @@
 module github.com/dugword/advent-of-code-2021
 
-go 1.17
+go 1.21
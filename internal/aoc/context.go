@@ -0,0 +1,64 @@
+package aoc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+)
+
+// ErrTimeout is returned when a solver does not finish before its deadline.
+var ErrTimeout = errors.New("timed out")
+
+// NotifyContext returns a context that is cancelled on interrupt, or once
+// timeout has passed when timeout is greater than zero.
+func NotifyContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	if timeout <= 0 {
+		return ctx, stop
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// Solve calls solve and waits for it to return or for ctx to be done,
+// whichever happens first. A solver that ignores ctx is left running in the
+// background when ctx is done. Solve returns ErrTimeout if the deadline of ctx
+// passed, or the error of ctx if it was cancelled.
+func Solve(ctx context.Context, solve SolveFunc, input io.Reader) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, contextError(err)
+	}
+	type result struct {
+		answer int
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		answer, err := solve(ctx, input)
+		done <- result{answer, err}
+	}()
+	select {
+	case r := <-done:
+		return r.answer, contextError(r.err)
+	case <-ctx.Done():
+		return 0, contextError(ctx.Err())
+	}
+}
+
+// IsStopped reports whether err is the result of a timeout or cancellation.
+func IsStopped(err error) bool {
+	return errors.Is(err, ErrTimeout) || errors.Is(err, context.Canceled)
+}
+
+func contextError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	return err
+}
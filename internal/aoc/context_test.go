@@ -0,0 +1,56 @@
+package aoc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+)
+
+func TestSolve(t *testing.T) {
+	ignoring := func(context.Context, io.Reader) (int, error) {
+		time.Sleep(time.Second)
+		return 1, nil
+	}
+	t.Run("return the answer", func(t *testing.T) {
+		got, err := aoc.Solve(context.Background(), constant(7), strings.NewReader(""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != 7 {
+			t.Errorf("got: %d, want: %d", got, 7)
+		}
+	})
+	t.Run("fail on timeout when the solver ignores the context", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		_, got := aoc.Solve(ctx, ignoring, strings.NewReader(""))
+		if !errors.Is(got, aoc.ErrTimeout) {
+			t.Errorf("got: %v, want: %v", got, aoc.ErrTimeout)
+		}
+	})
+	t.Run("fail on timeout returned by the solver", func(t *testing.T) {
+		expired := func(context.Context, io.Reader) (int, error) {
+			return 0, context.DeadlineExceeded
+		}
+		_, got := aoc.Solve(context.Background(), expired, strings.NewReader(""))
+		if !errors.Is(got, aoc.ErrTimeout) {
+			t.Errorf("got: %v, want: %v", got, aoc.ErrTimeout)
+		}
+	})
+	t.Run("fail on cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, got := aoc.Solve(ctx, ignoring, strings.NewReader(""))
+		if !errors.Is(got, context.Canceled) {
+			t.Errorf("got: %v, want: %v", got, context.Canceled)
+		}
+		if !aoc.IsStopped(got) {
+			t.Error("cancellation not reported as stopped")
+		}
+	})
+}
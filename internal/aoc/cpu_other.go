@@ -0,0 +1,11 @@
+//go:build windows || plan9 || js || wasip1
+// +build windows plan9 js wasip1
+
+package aoc
+
+import "time"
+
+// cpuTime is not available on this platform.
+func cpuTime() time.Duration {
+	return 0
+}
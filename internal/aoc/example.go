@@ -0,0 +1,68 @@
+package aoc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNoExample is returned when checking a Solver without an example.
+	ErrNoExample = errors.New("no example")
+	// ErrExample is returned when a Solver gives the wrong answer to its
+	// example.
+	ErrExample = errors.New("wrong answer to the example")
+)
+
+// Example from the description of a puzzle.
+type Example struct {
+	Input   []byte
+	Answers [2]int
+}
+
+// LoadExample reads example/input, example/part_1 and example/part_2 from
+// the Example of the Solver.
+func (s Solver) LoadExample() (Example, error) {
+	if s.Example == nil {
+		return Example{}, fmt.Errorf("day %d of %d: %w", s.Day, s.Year, ErrNoExample)
+	}
+	input, err := fs.ReadFile(s.Example, "example/input")
+	if err != nil {
+		return Example{}, err
+	}
+	example := Example{Input: input}
+	for i := range example.Answers {
+		path := fmt.Sprintf("example/part_%d", i+1)
+		contents, err := fs.ReadFile(s.Example, path)
+		if err != nil {
+			return Example{}, err
+		}
+		answer, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+		if err != nil {
+			return Example{}, fmt.Errorf("%s: %w", path, err)
+		}
+		example.Answers[i] = answer
+	}
+	return example, nil
+}
+
+// CheckExample solves a part of the example of a Solver, failing with
+// ErrExample unless it gives the answer from the puzzle description.
+func CheckExample(ctx context.Context, solver Solver, part int) error {
+	example, err := solver.LoadExample()
+	if err != nil {
+		return err
+	}
+	got, err := Solve(ctx, solver.Part(part), bytes.NewReader(example.Input))
+	if err != nil {
+		return fmt.Errorf("day %d part %d example: %w", solver.Day, part, err)
+	}
+	if want := example.Answers[part-1]; got != want {
+		return fmt.Errorf("day %d part %d: %w: got %d, want %d", solver.Day, part, ErrExample, got, want)
+	}
+	return nil
+}
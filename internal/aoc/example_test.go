@@ -0,0 +1,70 @@
+package aoc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+)
+
+// countLines answers with the number of lines in the input.
+func countLines(_ context.Context, input io.Reader) (int, error) {
+	contents, err := io.ReadAll(input)
+	return strings.Count(string(contents), "\n"), err
+}
+
+func exampleFS(input, part1, part2 string) fstest.MapFS {
+	return fstest.MapFS{
+		"example/input":  {Data: []byte(input)},
+		"example/part_1": {Data: []byte(part1)},
+		"example/part_2": {Data: []byte(part2)},
+	}
+}
+
+func TestLoadExample(t *testing.T) {
+	t.Run("load the input and answers", func(t *testing.T) {
+		solver := aoc.Solver{Example: exampleFS("a\nb\n", "2\n", "-4\n")}
+		got, err := solver.LoadExample()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got.Input) != "a\nb\n" || got.Answers != [2]int{2, -4} {
+			t.Errorf("got: %+v", got)
+		}
+	})
+	t.Run("fail without an example", func(t *testing.T) {
+		if _, got := (aoc.Solver{}).LoadExample(); !errors.Is(got, aoc.ErrNoExample) {
+			t.Errorf("got: %v, want: %v", got, aoc.ErrNoExample)
+		}
+	})
+	t.Run("fail on invalid answer", func(t *testing.T) {
+		solver := aoc.Solver{Example: exampleFS("", "two", "4")}
+		if _, got := solver.LoadExample(); got == nil || !strings.HasPrefix(got.Error(), "example/part_1:") {
+			t.Errorf("got: %v, want an error for example/part_1", got)
+		}
+	})
+}
+
+func TestCheckExample(t *testing.T) {
+	solver := aoc.Solver{
+		Day:     1,
+		Part1:   countLines,
+		Part2:   constant(7),
+		Example: exampleFS("a\nb\n", "2", "4"),
+	}
+	if err := aoc.CheckExample(context.Background(), solver, 1); err != nil {
+		t.Errorf("got: %v, want: %v", err, nil)
+	}
+	got := aoc.CheckExample(context.Background(), solver, 2)
+	if !errors.Is(got, aoc.ErrExample) {
+		t.Errorf("got: %v, want: %v", got, aoc.ErrExample)
+	}
+	want := "day 1 part 2: wrong answer to the example: got 7, want 4"
+	if got == nil || got.Error() != want {
+		t.Errorf("got: %v, want: %q", got, want)
+	}
+}
@@ -0,0 +1,89 @@
+package aoc
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit codes of every command.
+const (
+	// ExitOK on success, including a request for help.
+	ExitOK = 0
+	// ExitFailure for any error not covered by another code.
+	ExitFailure = 1
+	// ExitUsage for a mistake in the command line.
+	ExitUsage = 2
+	// ExitInput for a missing or invalid puzzle input.
+	ExitInput = 3
+	// ExitSolver for a solver that failed, timed out or gave a wrong answer.
+	ExitSolver = 4
+)
+
+// exitError gives an error an exit code without changing its message.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// UsageError marks err as a mistake in the command line.
+func UsageError(err error) error {
+	return &exitError{code: ExitUsage, err: err}
+}
+
+// InputError marks err as a missing or invalid puzzle input.
+func InputError(err error) error {
+	return &exitError{code: ExitInput, err: err}
+}
+
+// SolverError marks err as a failure of a solver.
+func SolverError(err error) error {
+	return &exitError{code: ExitSolver, err: err}
+}
+
+// ParseFlags parses args into flags, returning any error as a UsageError.
+func ParseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		return UsageError(err)
+	}
+	return nil
+}
+
+// ExitCode for the error returned by a command. A request for help, which the
+// flag set has already answered by printing its usage, is not a failure.
+func ExitCode(err error) int {
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return ExitFailure
+}
+
+// Exit writes the error returned by a command to stderr on its own line, unless
+// it is nil or a request for help, and returns its ExitCode.
+func Exit(stderr io.Writer, err error) int {
+	code := ExitCode(err)
+	if code != ExitOK {
+		fmt.Fprintln(stderr, err)
+	}
+	return code
+}
+
+// Main runs a command with the arguments and standard streams of the process
+// and exits with the ExitCode of its error.
+func Main(run func(args []string, stdout, stderr io.Writer) error) {
+	os.Exit(Exit(os.Stderr, run(os.Args, os.Stdout, os.Stderr)))
+}
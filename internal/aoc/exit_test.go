@@ -0,0 +1,99 @@
+package aoc_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+)
+
+func TestExitCode(t *testing.T) {
+	broken := errors.New("broken")
+	testCases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "success", err: nil, want: aoc.ExitOK},
+		{name: "help", err: flag.ErrHelp, want: aoc.ExitOK},
+		{name: "help as usage error", err: aoc.UsageError(flag.ErrHelp), want: aoc.ExitOK},
+		{name: "other error", err: broken, want: aoc.ExitFailure},
+		{name: "usage error", err: aoc.UsageError(broken), want: aoc.ExitUsage},
+		{name: "input error", err: aoc.InputError(broken), want: aoc.ExitInput},
+		{name: "solver error", err: aoc.SolverError(broken), want: aoc.ExitSolver},
+		{name: "wrapped solver error", err: fmt.Errorf("day 1: %w", aoc.SolverError(broken)), want: aoc.ExitSolver},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := aoc.ExitCode(testCase.err); got != testCase.want {
+				t.Errorf("got: %d, want: %d", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestExitError(t *testing.T) {
+	broken := errors.New("broken")
+	err := aoc.InputError(broken)
+	if err.Error() != "broken" {
+		t.Errorf("got: %q, want: %q", err, "broken")
+	}
+	if !errors.Is(err, broken) {
+		t.Errorf("got: %v, want it to wrap: %v", err, broken)
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	newFlags := func() *flag.FlagSet {
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.SetOutput(io.Discard)
+		flags.Bool("v", false, "verbose")
+		return flags
+	}
+	t.Run("parse without failure", func(t *testing.T) {
+		if err := aoc.ParseFlags(newFlags(), []string{"-v"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("fail on invalid flag", func(t *testing.T) {
+		got := aoc.ParseFlags(newFlags(), []string{"-invalid"})
+		if code := aoc.ExitCode(got); code != aoc.ExitUsage {
+			t.Errorf("got: %d, want: %d", code, aoc.ExitUsage)
+		}
+	})
+	t.Run("succeed on help", func(t *testing.T) {
+		got := aoc.ParseFlags(newFlags(), []string{"-h"})
+		if !errors.Is(got, flag.ErrHelp) {
+			t.Errorf("got: %v, want: %v", got, flag.ErrHelp)
+		}
+		if code := aoc.ExitCode(got); code != aoc.ExitOK {
+			t.Errorf("got: %d, want: %d", code, aoc.ExitOK)
+		}
+	})
+}
+
+func TestExit(t *testing.T) {
+	t.Run("write error on its own line", func(t *testing.T) {
+		var stderr bytes.Buffer
+		got := aoc.Exit(&stderr, aoc.InputError(errors.New("invalid input file")))
+		if got != aoc.ExitInput {
+			t.Errorf("got: %d, want: %d", got, aoc.ExitInput)
+		}
+		if stderr.String() != "invalid input file\n" {
+			t.Errorf("got: %q, want: %q", stderr.String(), "invalid input file\n")
+		}
+	})
+	t.Run("write nothing on help", func(t *testing.T) {
+		var stderr bytes.Buffer
+		if got := aoc.Exit(&stderr, flag.ErrHelp); got != aoc.ExitOK {
+			t.Errorf("got: %d, want: %d", got, aoc.ExitOK)
+		}
+		if stderr.Len() != 0 {
+			t.Errorf("got: %q, want nothing", stderr.String())
+		}
+	})
+}
@@ -0,0 +1,16 @@
+package aoc
+
+import (
+	"flag"
+	"io"
+	"math/rand"
+)
+
+// Generator of random, valid puzzle inputs for a day.
+type Generator interface {
+	// Register the flags for the generator's parameters.
+	Register(flags *flag.FlagSet)
+	// Generate writes a random input using rng and returns the answers to
+	// both parts, computed independently of the day's solver.
+	Generate(w io.Writer, rng *rand.Rand) (answers [2]int, err error)
+}
@@ -0,0 +1,136 @@
+package aoc
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/dugword/advent-of-code-2021/internal/inputs"
+	"github.com/dugword/advent-of-code-2021/internal/logging"
+)
+
+// Options shared by the command line of every day.
+type Options struct {
+	Timeout    time.Duration
+	CPUProfile string
+	MemProfile string
+	Trace      string
+	Verbose    bool
+	JSON       bool
+	// CheckExample checks the answer to the example of the puzzle before
+	// solving the input.
+	CheckExample bool
+	// LogLevel of the records logged to stderr, defaulting to debug when
+	// Verbose is set and warn otherwise.
+	LogLevel string
+	// LogFormat of the records logged to stderr, defaulting to text.
+	LogFormat string
+	// Stdin is read for an input file of "-", defaulting to os.Stdin.
+	Stdin io.Reader
+}
+
+// Answer is the JSON form of an answer, written when the JSON option is set.
+type Answer struct {
+	Year   int    `json:"year"`
+	Day    int    `json:"day"`
+	Part   int    `json:"part"`
+	Answer int    `json:"answer"`
+	Error  string `json:"error,omitempty"`
+	Cached bool   `json:"cached,omitempty"`
+}
+
+// Register the flags for each option, defaulting to its current value.
+func (o *Options) Register(flags *flag.FlagSet) {
+	flags.DurationVar(&o.Timeout, "timeout", o.Timeout, "stop solving after this long, 0 for no limit")
+	flags.StringVar(&o.CPUProfile, "cpuprofile", o.CPUProfile, "write a CPU profile of the solve to this file")
+	flags.StringVar(&o.MemProfile, "memprofile", o.MemProfile, "write a memory profile of the solve to this file")
+	flags.StringVar(&o.Trace, "trace", o.Trace, "write an execution trace of the solve to this file")
+	flags.BoolVar(&o.Verbose, "v", o.Verbose, "report allocations and peak heap, and log at debug level, to stderr")
+	flags.BoolVar(&o.JSON, "json", o.JSON, "write answers as JSON")
+	flags.BoolVar(&o.CheckExample, "check-example", o.CheckExample, "check the answer to the example of the puzzle before solving the input")
+	flags.Func("log-level", "log records at or above this `level` to stderr: trace, debug, info, warn or error", func(s string) error {
+		if _, err := logging.ParseLevel(s); err != nil {
+			return err
+		}
+		o.LogLevel = s
+		return nil
+	})
+	flags.Func("log-format", "`format` of the records logged to stderr: text or json", func(s string) error {
+		format, err := logging.ParseFormat(s)
+		o.LogFormat = format
+		return err
+	})
+}
+
+// Logger returns a logger writing to stderr at the level and in the format
+// set by the options.
+func (o *Options) Logger(stderr io.Writer) *slog.Logger {
+	level := slog.LevelWarn
+	if o.Verbose {
+		level = slog.LevelDebug
+	}
+	if o.LogLevel != "" {
+		if parsed, err := logging.ParseLevel(o.LogLevel); err == nil {
+			level = parsed
+		}
+	}
+	format := o.LogFormat
+	if format == "" {
+		format = logging.FormatText
+	}
+	return logging.New(stderr, level, format)
+}
+
+// Solve one part of a puzzle from an input file, or stdin if the path is "-",
+// decrypting the input file if only an encrypted copy exists, stopping on
+// interrupt or once the timeout has passed, and profiling and logging the
+// solve to stderr as requested by the options. Errors opening or solving the
+// input are marked as an InputError, unless the solve was stopped, and errors
+// profiling it are returned as they are.
+func (o *Options) Solve(solve SolveFunc, inputFilepath string, stderr io.Writer) (int, error) {
+	var input io.Reader = o.Stdin
+	if input == nil {
+		input = os.Stdin
+	}
+	if inputFilepath != "-" {
+		file, err := inputs.Open(inputFilepath)
+		if err != nil {
+			return 0, InputError(err)
+		}
+		defer file.Close()
+		input = file
+	}
+	ctx, cancel := NotifyContext(o.Timeout)
+	defer cancel()
+	logger := o.Logger(stderr)
+	ctx = logging.NewContext(ctx, logger)
+	start := time.Now()
+	var answer int
+	var solveErr error
+	err := o.Profile(stderr, func() error {
+		answer, solveErr = Solve(ctx, solve, input)
+		return solveErr
+	})
+	if err != nil && err == solveErr && !IsStopped(err) {
+		err = InputError(err)
+	}
+	if err != nil {
+		logger.InfoContext(ctx, "failed", "error", err, "duration", time.Since(start))
+		return 0, err
+	}
+	logger.InfoContext(ctx, "solved", "answer", answer, "duration", time.Since(start))
+	return answer, nil
+}
+
+// WriteAnswer to stdout, as an Answer when the JSON option is set.
+func (o *Options) WriteAnswer(stdout io.Writer, year, day, part, answer int) error {
+	if o.JSON {
+		return json.NewEncoder(stdout).Encode(Answer{Year: year, Day: day, Part: part, Answer: answer})
+	}
+	_, err := fmt.Fprintf(stdout, "%d\n", answer)
+	return err
+}
@@ -0,0 +1,189 @@
+package aoc_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/logging"
+)
+
+func TestOptionsRegister(t *testing.T) {
+	var options aoc.Options
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	options.Register(flags)
+	args := []string{
+		"-timeout", "2s",
+		"-cpuprofile", "cpu.out",
+		"-memprofile", "mem.out",
+		"-trace", "trace.out",
+		"-v",
+		"-json",
+		"-check-example",
+		"-log-level", "trace",
+		"-log-format", "json",
+	}
+	if err := flags.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	want := aoc.Options{
+		Timeout:      2 * time.Second,
+		CPUProfile:   "cpu.out",
+		MemProfile:   "mem.out",
+		Trace:        "trace.out",
+		Verbose:      true,
+		JSON:         true,
+		CheckExample: true,
+		LogLevel:     "trace",
+		LogFormat:    "json",
+	}
+	if options != want {
+		t.Errorf("got: %+v, want: %+v", options, want)
+	}
+	for _, args := range [][]string{{"-log-level", "loud"}, {"-log-format", "xml"}} {
+		if err := flags.Parse(args); err == nil {
+			t.Errorf("%v did not fail as expected", args)
+		}
+	}
+}
+
+func TestOptionsLogger(t *testing.T) {
+	testCases := []struct {
+		name    string
+		options aoc.Options
+		level   slog.Level
+		want    bool
+	}{
+		{name: "warn by default", options: aoc.Options{}, level: slog.LevelWarn, want: true},
+		{name: "not info by default", options: aoc.Options{}, level: slog.LevelInfo, want: false},
+		{name: "debug when verbose", options: aoc.Options{Verbose: true}, level: slog.LevelDebug, want: true},
+		{name: "level over verbose", options: aoc.Options{Verbose: true, LogLevel: "error"}, level: slog.LevelWarn, want: false},
+		{name: "trace", options: aoc.Options{LogLevel: "trace"}, level: logging.LevelTrace, want: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			logger := testCase.options.Logger(io.Discard)
+			if got := logger.Enabled(context.Background(), testCase.level); got != testCase.want {
+				t.Errorf("got: %v, want: %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestOptionsSolve(t *testing.T) {
+	t.Run("solve without failure", func(t *testing.T) {
+		var options aoc.Options
+		var stderr bytes.Buffer
+		got, err := options.Solve(constant(7), tempInput(t), &stderr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != 7 {
+			t.Errorf("got: %d, want: %d", got, 7)
+		}
+	})
+	t.Run("solve from stdin", func(t *testing.T) {
+		options := aoc.Options{Stdin: strings.NewReader("7")}
+		read := func(_ context.Context, input io.Reader) (int, error) {
+			contents, err := io.ReadAll(input)
+			return len(contents), err
+		}
+		var stderr bytes.Buffer
+		got, err := options.Solve(read, "-", &stderr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != 1 {
+			t.Errorf("got: %d, want: %d", got, 1)
+		}
+	})
+	t.Run("log the solve to stderr", func(t *testing.T) {
+		options := aoc.Options{LogLevel: "debug", LogFormat: "json"}
+		record := func(ctx context.Context, _ io.Reader) (int, error) {
+			aoc.Record(ctx, "seven", 7)
+			return 7, nil
+		}
+		var stderr bytes.Buffer
+		if _, err := options.Solve(record, tempInput(t), &stderr); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{`"msg":"value","name":"seven","value":7`, `"msg":"solved","answer":7`} {
+			if !strings.Contains(stderr.String(), want) {
+				t.Errorf("got: %q, want to contain: %q", stderr.String(), want)
+			}
+		}
+	})
+	t.Run("fail on missing file", func(t *testing.T) {
+		var options aoc.Options
+		var stderr bytes.Buffer
+		_, got := options.Solve(constant(7), "./testdata/missing", &stderr)
+		if code := aoc.ExitCode(got); code != aoc.ExitInput {
+			t.Errorf("got: %v with exit code %d, want exit code: %d", got, code, aoc.ExitInput)
+		}
+	})
+	t.Run("fail on invalid input", func(t *testing.T) {
+		var options aoc.Options
+		invalid := func(context.Context, io.Reader) (int, error) {
+			return 0, errors.New("invalid")
+		}
+		var stderr bytes.Buffer
+		_, got := options.Solve(invalid, tempInput(t), &stderr)
+		if code := aoc.ExitCode(got); code != aoc.ExitInput {
+			t.Errorf("got: %v with exit code %d, want exit code: %d", got, code, aoc.ExitInput)
+		}
+	})
+	t.Run("fail on unwritable profile without blaming the input", func(t *testing.T) {
+		options := aoc.Options{CPUProfile: filepath.Join(t.TempDir(), "missing", "cpu.prof")}
+		var stderr bytes.Buffer
+		_, got := options.Solve(constant(7), tempInput(t), &stderr)
+		if code := aoc.ExitCode(got); code != aoc.ExitFailure {
+			t.Errorf("got: %v with exit code %d, want exit code: %d", got, code, aoc.ExitFailure)
+		}
+	})
+	t.Run("fail on timeout", func(t *testing.T) {
+		options := aoc.Options{Timeout: time.Millisecond}
+		blocking := func(ctx context.Context, _ io.Reader) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}
+		var stderr bytes.Buffer
+		_, got := options.Solve(blocking, tempInput(t), &stderr)
+		if !errors.Is(got, aoc.ErrTimeout) {
+			t.Errorf("got: %v, want: %v", got, aoc.ErrTimeout)
+		}
+	})
+}
+
+func TestOptionsWriteAnswer(t *testing.T) {
+	testCases := []struct {
+		options aoc.Options
+		want    string
+	}{
+		{
+			options: aoc.Options{},
+			want:    "42\n",
+		},
+		{
+			options: aoc.Options{JSON: true},
+			want:    `{"year":2021,"day":3,"part":2,"answer":42}` + "\n",
+		},
+	}
+	for i, testCase := range testCases {
+		var stdout bytes.Buffer
+		if err := testCase.options.WriteAnswer(&stdout, 2021, 3, 2, 42); err != nil {
+			t.Fatal(err)
+		}
+		if got := stdout.String(); got != testCase.want {
+			t.Errorf("test case %d got: %q, want: %q", i, got, testCase.want)
+		}
+	}
+}
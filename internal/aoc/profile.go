@@ -0,0 +1,166 @@
+package aoc
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/metrics"
+	"runtime/pprof"
+	"runtime/trace"
+	"time"
+)
+
+// heapObjectsMetric is the number of bytes occupied by live and unswept
+// objects on the heap.
+const heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+
+// Profile calls f with the CPU profile and execution trace requested by the
+// options running, then writes the memory profile. In verbose mode the
+// allocations made and the peak heap size while f ran are reported to stderr.
+func (o *Options) Profile(stderr io.Writer, f func() error) error {
+	stopCPUProfile, err := startCPUProfile(o.CPUProfile)
+	if err != nil {
+		return err
+	}
+	stopTrace, err := startTrace(o.Trace)
+	if err != nil {
+		stopCPUProfile()
+		return err
+	}
+	var stats *memStats
+	if o.Verbose {
+		stats = startMemStats()
+	}
+	err = f()
+	if stats != nil {
+		stats.stop()
+	}
+	if stopErr := stopTrace(); err == nil {
+		err = stopErr
+	}
+	if stopErr := stopCPUProfile(); err == nil {
+		err = stopErr
+	}
+	if err != nil {
+		return err
+	}
+	if stats != nil {
+		fmt.Fprintf(stderr, "allocs: %d (%s), peak heap: %s\n", stats.mallocs, formatBytes(stats.totalAlloc), formatBytes(stats.peakHeap))
+	}
+	return writeMemProfile(o.MemProfile)
+}
+
+func startCPUProfile(filepath string) (stop func() error, err error) {
+	if filepath == "" {
+		return func() error { return nil }, nil
+	}
+	file, err := os.Create(filepath)
+	if err != nil {
+		return nil, err
+	}
+	if err := pprof.StartCPUProfile(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return func() error {
+		pprof.StopCPUProfile()
+		return file.Close()
+	}, nil
+}
+
+func startTrace(filepath string) (stop func() error, err error) {
+	if filepath == "" {
+		return func() error { return nil }, nil
+	}
+	file, err := os.Create(filepath)
+	if err != nil {
+		return nil, err
+	}
+	if err := trace.Start(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return func() error {
+		trace.Stop()
+		return file.Close()
+	}, nil
+}
+
+func writeMemProfile(filepath string) error {
+	if filepath == "" {
+		return nil
+	}
+	file, err := os.Create(filepath)
+	if err != nil {
+		return err
+	}
+	runtime.GC()
+	if err := pprof.WriteHeapProfile(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// memStats samples the heap while a solve runs.
+type memStats struct {
+	start      runtime.MemStats
+	mallocs    uint64
+	totalAlloc uint64
+	peakHeap   uint64
+	done       chan struct{}
+	sampled    chan uint64
+}
+
+func startMemStats() *memStats {
+	s := &memStats{
+		done:    make(chan struct{}),
+		sampled: make(chan uint64),
+	}
+	runtime.ReadMemStats(&s.start)
+	go func() {
+		sample := []metrics.Sample{{Name: heapObjectsMetric}}
+		peak := uint64(0)
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			metrics.Read(sample)
+			if sample[0].Value.Kind() == metrics.KindUint64 && sample[0].Value.Uint64() > peak {
+				peak = sample[0].Value.Uint64()
+			}
+			select {
+			case <-s.done:
+				s.sampled <- peak
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s
+}
+
+func (s *memStats) stop() {
+	close(s.done)
+	s.peakHeap = <-s.sampled
+	var end runtime.MemStats
+	runtime.ReadMemStats(&end)
+	s.mallocs = end.Mallocs - s.start.Mallocs
+	s.totalAlloc = end.TotalAlloc - s.start.TotalAlloc
+	if end.HeapAlloc > s.peakHeap {
+		s.peakHeap = end.HeapAlloc
+	}
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,73 @@
+package aoc_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+)
+
+func TestProfile(t *testing.T) {
+	t.Run("write profiles and stats", func(t *testing.T) {
+		dir := t.TempDir()
+		options := aoc.Options{
+			CPUProfile: filepath.Join(dir, "cpu.out"),
+			MemProfile: filepath.Join(dir, "mem.out"),
+			Trace:      filepath.Join(dir, "trace.out"),
+			Verbose:    true,
+		}
+		var stderr bytes.Buffer
+		var sink [][]byte
+		err := options.Profile(&stderr, func() error {
+			for i := 0; i < 100; i++ {
+				sink = append(sink, make([]byte, 1024))
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"cpu.out", "mem.out", "trace.out"} {
+			info, err := os.Stat(filepath.Join(dir, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Size() == 0 {
+				t.Errorf("%s is empty", name)
+			}
+		}
+		if !strings.HasPrefix(stderr.String(), "allocs: ") {
+			t.Errorf("missing stats in %q", stderr.String())
+		}
+	})
+	t.Run("skip profiles when not requested", func(t *testing.T) {
+		var options aoc.Options
+		var stderr bytes.Buffer
+		if err := options.Profile(&stderr, func() error { return nil }); err != nil {
+			t.Fatal(err)
+		}
+		if stderr.Len() != 0 {
+			t.Errorf("got: %q, want no output", stderr.String())
+		}
+	})
+	t.Run("fail on error from f", func(t *testing.T) {
+		want := errors.New("broken")
+		var options aoc.Options
+		var stderr bytes.Buffer
+		got := options.Profile(&stderr, func() error { return want })
+		if got != want {
+			t.Errorf("got: %v, want: %v", got, want)
+		}
+	})
+	t.Run("fail on unwritable profile", func(t *testing.T) {
+		options := aoc.Options{CPUProfile: filepath.Join(t.TempDir(), "missing", "cpu.out")}
+		var stderr bytes.Buffer
+		if got := options.Profile(&stderr, func() error { return nil }); got == nil {
+			t.Error("did not fail as expected")
+		}
+	})
+}
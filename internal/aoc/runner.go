@@ -0,0 +1,215 @@
+package aoc
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dugword/advent-of-code-2021/internal/inputs"
+	"github.com/dugword/advent-of-code-2021/internal/logging"
+)
+
+// Result of solving one part of one day.
+type Result struct {
+	Year   int
+	Day    int
+	Part   int
+	Answer int
+	Err    error
+	Wall   time.Duration
+	// InputHash is the hex encoded SHA-256 of the input, or empty if it could
+	// not be read.
+	InputHash string
+	// Allocs is the number of heap allocations made while the part was
+	// solved. It is only measured by a runner with one worker, since the
+	// count would include the parts solved at the same time, and is zero
+	// otherwise.
+	Allocs uint64
+	// Cached is set when the answer came from the cache of the runner.
+	Cached bool
+}
+
+// Report of a RunAll, with results ordered by year, day and part.
+type Report struct {
+	Results []Result
+	Wall    time.Duration
+	CPU     time.Duration
+}
+
+// Failures counts the results that returned an error.
+func (r Report) Failures() int {
+	failures := 0
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failures++
+		}
+	}
+	return failures
+}
+
+// WriteSummary writes the report as a table followed by the total wall and
+// CPU time.
+func (r Report) WriteSummary(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "YEAR\tDAY\tPART\tANSWER\tWALL")
+	for _, result := range r.Results {
+		answer := fmt.Sprint(result.Answer)
+		if result.Err != nil {
+			answer = "error: " + result.Err.Error()
+		}
+		wall := result.Wall.Round(time.Microsecond).String()
+		if result.Cached {
+			wall += " (cached)"
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%s\t%s\n", result.Year, result.Day, result.Part, answer, wall)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "total wall: %s, total cpu: %s\n", r.Wall.Round(time.Microsecond), r.CPU.Round(time.Microsecond))
+	return err
+}
+
+// WriteJSON writes each result as an Answer on its own line.
+func (r Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	for _, result := range r.Results {
+		answer := Answer{
+			Year:   result.Year,
+			Day:    result.Day,
+			Part:   result.Part,
+			Answer: result.Answer,
+			Cached: result.Cached,
+		}
+		if result.Err != nil {
+			answer.Error = result.Err.Error()
+		}
+		if err := encoder.Encode(answer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Runner solves the parts of many puzzles in parallel.
+type Runner struct {
+	// InputFilepath returns the path to the input file for a day of a year.
+	InputFilepath func(year, day int) string
+	// Workers is the number of parts solved at once, at least one.
+	Workers int
+	// Timeout for each part, or zero for no limit.
+	Timeout time.Duration
+	// Cache of answers, or nil to solve every part.
+	Cache Cache
+	// CheckExamples checks the answer to the example of each part before its
+	// input, failing the part if it is wrong.
+	CheckExamples bool
+}
+
+// Cache of answers keyed by the year, day and part and the hex encoded
+// SHA-256 of the input.
+type Cache interface {
+	Get(year, day, part int, inputHash string) (answer int, ok bool)
+	Put(year, day, part int, inputHash string, answer int) error
+}
+
+// RunAll solves both parts of every solver, or takes their answers from the
+// cache if it has them for the same input. A part that fails or times out is
+// recorded in its Result and does not stop the others. Parts that have not
+// started when ctx is cancelled are not run. Each part is logged to the
+// logger in ctx.
+func (r Runner) RunAll(ctx context.Context, solvers []Solver) Report {
+	workers := r.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]Result, 0, 2*len(solvers))
+	for _, solver := range solvers {
+		for part := 1; part <= 2; part++ {
+			results = append(results, Result{Year: solver.Year, Day: solver.Day, Part: part})
+		}
+	}
+	start := time.Now()
+	startCPU := cpuTime()
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				r.solve(ctx, solvers[j/2], &results[j])
+			}
+		}()
+	}
+	for j := range results {
+		jobs <- j
+	}
+	close(jobs)
+	wg.Wait()
+	return Report{
+		Results: results,
+		Wall:    time.Since(start),
+		CPU:     cpuTime() - startCPU,
+	}
+}
+
+func (r Runner) solve(ctx context.Context, solver Solver, result *Result) {
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+	logger := logging.FromContext(ctx).With("year", solver.Year, "day", solver.Day, "part", result.Part)
+	ctx = logging.NewContext(ctx, logger)
+	start := time.Now()
+	defer func() {
+		result.Wall = time.Since(start)
+		if result.Err != nil {
+			logger.InfoContext(ctx, "failed", "error", result.Err, "duration", result.Wall)
+			return
+		}
+		logger.InfoContext(ctx, "solved", "answer", result.Answer, "duration", result.Wall, "cached", result.Cached)
+	}()
+	if r.CheckExamples {
+		if err := CheckExample(ctx, solver, result.Part); err != nil {
+			result.Err = err
+			return
+		}
+	}
+	input, err := inputs.ReadFile(r.InputFilepath(solver.Year, solver.Day))
+	if err != nil {
+		result.Err = err
+		return
+	}
+	hash := sha256.Sum256(input)
+	result.InputHash = hex.EncodeToString(hash[:])
+	if r.Cache != nil {
+		if answer, ok := r.Cache.Get(solver.Year, solver.Day, result.Part, result.InputHash); ok {
+			result.Answer, result.Cached = answer, true
+			return
+		}
+	}
+	if r.Workers > 1 {
+		result.Answer, result.Err = Solve(ctx, solver.Part(result.Part), bytes.NewReader(input))
+	} else {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		result.Answer, result.Err = Solve(ctx, solver.Part(result.Part), bytes.NewReader(input))
+		runtime.ReadMemStats(&after)
+		result.Allocs = after.Mallocs - before.Mallocs
+	}
+	if r.Cache != nil && result.Err == nil {
+		if err := r.Cache.Put(solver.Year, solver.Day, result.Part, result.InputHash, result.Answer); err != nil {
+			logger.WarnContext(ctx, "not cached", "error", err)
+		}
+	}
+}
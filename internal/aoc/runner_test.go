@@ -0,0 +1,235 @@
+package aoc_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+)
+
+func TestRunAll(t *testing.T) {
+	failing := func(context.Context, io.Reader) (int, error) {
+		return 0, errors.New("broken")
+	}
+	solvers := []aoc.Solver{
+		{Year: 2021, Day: 1, Part1: constant(11), Part2: constant(12)},
+		{Year: 2021, Day: 2, Part1: failing, Part2: constant(22)},
+		{Year: 2022, Day: 1, Part1: constant(31), Part2: constant(32)},
+	}
+	inputFilepath := tempInput(t)
+	for _, workers := range []int{0, 1, 4} {
+		runner := aoc.Runner{
+			InputFilepath: func(year, day int) string {
+				return inputFilepath
+			},
+			Workers: workers,
+		}
+		report := runner.RunAll(context.Background(), solvers)
+		want := []int{11, 12, 0, 22, 31, 32}
+		if len(report.Results) != len(want) {
+			t.Fatalf("got %d results, want %d", len(report.Results), len(want))
+		}
+		for i, result := range report.Results {
+			if result.Year != solvers[i/2].Year || result.Day != solvers[i/2].Day || result.Part != i%2+1 {
+				t.Errorf("result %d is %d day %d part %d", i, result.Year, result.Day, result.Part)
+			}
+			if result.Answer != want[i] {
+				t.Errorf("got: %d, want: %d", result.Answer, want[i])
+			}
+		}
+		if got := report.Failures(); got != 1 {
+			t.Errorf("got: %d failures, want: 1", got)
+		}
+		// The SHA-256 of the empty input.
+		wantHash := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+		if got := report.Results[0].InputHash; got != wantHash {
+			t.Errorf("got: %s, want: %s", got, wantHash)
+		}
+	}
+}
+
+func TestRunAllAllocs(t *testing.T) {
+	allocating := func(context.Context, io.Reader) (int, error) {
+		values := make([]*int, 100)
+		for i := range values {
+			values[i] = new(int)
+		}
+		return len(values), nil
+	}
+	solvers := []aoc.Solver{{Year: 2021, Day: 1, Part1: allocating, Part2: allocating}}
+	inputFilepath := tempInput(t)
+	testCases := []struct {
+		workers int
+		want    bool
+	}{
+		{workers: 0, want: true},
+		{workers: 1, want: true},
+		{workers: 2, want: false},
+	}
+	for _, testCase := range testCases {
+		runner := aoc.Runner{
+			InputFilepath: func(year, day int) string {
+				return inputFilepath
+			},
+			Workers: testCase.workers,
+		}
+		for _, result := range runner.RunAll(context.Background(), solvers).Results {
+			if got := result.Allocs > 0; got != testCase.want {
+				t.Errorf("%d workers part %d got allocs: %d, want measured: %v", testCase.workers, result.Part, result.Allocs, testCase.want)
+			}
+		}
+	}
+}
+
+// mapCache is a Cache in memory.
+type mapCache map[string]int
+
+func (c mapCache) key(year, day, part int, inputHash string) string {
+	return fmt.Sprintf("%d/%d/%d/%s", year, day, part, inputHash)
+}
+
+func (c mapCache) Get(year, day, part int, inputHash string) (int, bool) {
+	answer, ok := c[c.key(year, day, part, inputHash)]
+	return answer, ok
+}
+
+func (c mapCache) Put(year, day, part int, inputHash string, answer int) error {
+	c[c.key(year, day, part, inputHash)] = answer
+	return nil
+}
+
+func TestRunAllCache(t *testing.T) {
+	solves := 0
+	counting := func(context.Context, io.Reader) (int, error) {
+		solves++
+		return 7, nil
+	}
+	failing := func(context.Context, io.Reader) (int, error) {
+		return 0, errors.New("broken")
+	}
+	solvers := []aoc.Solver{{Year: 2021, Day: 1, Part1: counting, Part2: failing}}
+	inputFilepath := tempInput(t)
+	runner := aoc.Runner{
+		InputFilepath: func(year, day int) string {
+			return inputFilepath
+		},
+		Cache: mapCache{},
+	}
+	first := runner.RunAll(context.Background(), solvers)
+	second := runner.RunAll(context.Background(), solvers)
+	if solves != 1 {
+		t.Errorf("got: %d solves, want: 1", solves)
+	}
+	if first.Results[0].Cached || !second.Results[0].Cached || second.Results[0].Answer != 7 {
+		t.Errorf("got: %+v then %+v, want part 1 cached the second time", first.Results[0], second.Results[0])
+	}
+	if second.Results[1].Cached || second.Results[1].Err == nil {
+		t.Errorf("got: %+v, want part 2 to fail again", second.Results[1])
+	}
+}
+
+func TestRunAllCheckExamples(t *testing.T) {
+	solvers := []aoc.Solver{
+		{Year: 2021, Day: 1, Part1: countLines, Part2: countLines, Example: exampleFS("a\n", "1", "2")},
+	}
+	inputFilepath := tempInput(t)
+	runner := aoc.Runner{
+		InputFilepath: func(year, day int) string {
+			return inputFilepath
+		},
+		CheckExamples: true,
+	}
+	report := runner.RunAll(context.Background(), solvers)
+	if err := report.Results[0].Err; err != nil {
+		t.Errorf("part 1 failed: %v", err)
+	}
+	if err := report.Results[1].Err; !errors.Is(err, aoc.ErrExample) {
+		t.Errorf("got: %v, want: %v", err, aoc.ErrExample)
+	}
+}
+
+func TestRunAllTimeout(t *testing.T) {
+	blocking := func(ctx context.Context, _ io.Reader) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	solvers := []aoc.Solver{
+		{Day: 1, Part1: constant(11), Part2: blocking},
+	}
+	inputFilepath := tempInput(t)
+	runner := aoc.Runner{
+		InputFilepath: func(year, day int) string {
+			return inputFilepath
+		},
+		Workers: 2,
+		Timeout: time.Millisecond,
+	}
+	report := runner.RunAll(context.Background(), solvers)
+	if err := report.Results[0].Err; err != nil {
+		t.Errorf("part 1 failed: %v", err)
+	}
+	if err := report.Results[1].Err; !errors.Is(err, aoc.ErrTimeout) {
+		t.Errorf("got: %v, want: %v", err, aoc.ErrTimeout)
+	}
+}
+
+func TestRunAllMissingInput(t *testing.T) {
+	solvers := []aoc.Solver{
+		{Day: 1, Part1: constant(11), Part2: constant(12)},
+	}
+	runner := aoc.Runner{
+		InputFilepath: func(year, day int) string {
+			return "./testdata/missing"
+		},
+	}
+	report := runner.RunAll(context.Background(), solvers)
+	if got := report.Failures(); got != 2 {
+		t.Errorf("got: %d failures, want: 2", got)
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	report := aoc.Report{
+		Results: []aoc.Result{
+			{Year: 2021, Day: 1, Part: 1, Answer: 7},
+			{Year: 2021, Day: 1, Part: 2, Err: errors.New("broken")},
+			{Year: 2021, Day: 2, Part: 1, Answer: 8, Cached: true},
+		},
+	}
+	var buf bytes.Buffer
+	if err := report.WriteSummary(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	for _, want := range []string{"YEAR", "DAY", "2021", "7", "error: broken", "(cached)", "total wall:"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary %q does not contain %q", got, want)
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	report := aoc.Report{
+		Results: []aoc.Result{
+			{Year: 2021, Day: 1, Part: 1, Answer: 7},
+			{Year: 2021, Day: 1, Part: 2, Err: errors.New("broken")},
+			{Year: 2021, Day: 2, Part: 1, Answer: 8, Cached: true},
+		},
+	}
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"year":2021,"day":1,"part":1,"answer":7}` + "\n" +
+		`{"year":2021,"day":1,"part":2,"answer":0,"error":"broken"}` + "\n" +
+		`{"year":2021,"day":2,"part":1,"answer":8,"cached":true}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+}
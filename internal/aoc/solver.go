@@ -0,0 +1,97 @@
+// Package aoc provides the solver registry and runner shared by every day.
+package aoc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"sync"
+)
+
+// SolveFunc computes the answer to one part of a puzzle from its input. Long
+// running solvers should return early once ctx is done.
+type SolveFunc func(ctx context.Context, input io.Reader) (int, error)
+
+// Solver for both parts of a single day's puzzle.
+type Solver struct {
+	Year  int
+	Day   int
+	Part1 SolveFunc
+	Part2 SolveFunc
+	// NewGenerator returns a Generator of inputs with default parameters, or
+	// is nil if the day has no generator.
+	NewGenerator func() Generator
+	// Example holds the example from the puzzle description, with its input
+	// in example/input and its answers in example/part_1 and example/part_2,
+	// or is nil if the day has none.
+	Example fs.FS
+}
+
+// Part returns the SolveFunc for part 1 or 2 of the puzzle.
+func (s Solver) Part(part int) SolveFunc {
+	if part == 2 {
+		return s.Part2
+	}
+	return s.Part1
+}
+
+// key of a Solver in the registry.
+type key struct {
+	year int
+	day  int
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[key]Solver{}
+)
+
+// Register a Solver so it can be found by the runner. Register panics if a
+// Solver for the same year and day has already been registered.
+func Register(solver Solver) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	k := key{year: solver.Year, day: solver.Day}
+	if _, ok := registry[k]; ok {
+		panic(fmt.Sprintf("aoc: solver for %d day %d registered twice", solver.Year, solver.Day))
+	}
+	registry[k] = solver
+}
+
+// Lookup the Solver registered for a day of a year.
+func Lookup(year, day int) (Solver, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	solver, ok := registry[key{year: year, day: day}]
+	return solver, ok
+}
+
+// Solvers returns every registered Solver ordered by year and day.
+func Solvers() []Solver {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	solvers := make([]Solver, 0, len(registry))
+	for _, solver := range registry {
+		solvers = append(solvers, solver)
+	}
+	sort.Slice(solvers, func(i, j int) bool {
+		if solvers[i].Year != solvers[j].Year {
+			return solvers[i].Year < solvers[j].Year
+		}
+		return solvers[i].Day < solvers[j].Day
+	})
+	return solvers
+}
+
+// YearSolvers returns the registered Solvers of a year ordered by day.
+func YearSolvers(year int) []Solver {
+	var solvers []Solver
+	for _, solver := range Solvers() {
+		if solver.Year == year {
+			solvers = append(solvers, solver)
+		}
+	}
+	return solvers
+}
@@ -0,0 +1,75 @@
+package aoc_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+)
+
+func constant(answer int) aoc.SolveFunc {
+	return func(context.Context, io.Reader) (int, error) {
+		return answer, nil
+	}
+}
+
+func tempInput(t *testing.T) string {
+	t.Helper()
+	inputFilepath := filepath.Join(t.TempDir(), "input")
+	if err := os.WriteFile(inputFilepath, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return inputFilepath
+}
+
+func TestRegister(t *testing.T) {
+	t.Run("register and look up solvers ordered by year and day", func(t *testing.T) {
+		aoc.Register(aoc.Solver{Year: 2099, Day: 102, Part1: constant(1), Part2: constant(2)})
+		aoc.Register(aoc.Solver{Year: 2099, Day: 101, Part1: constant(1), Part2: constant(2)})
+		aoc.Register(aoc.Solver{Year: 2098, Day: 102, Part1: constant(1), Part2: constant(2)})
+		if _, ok := aoc.Lookup(2099, 101); !ok {
+			t.Fatal("did not find registered solver")
+		}
+		var got [][2]int
+		for _, solver := range aoc.Solvers() {
+			got = append(got, [2]int{solver.Year, solver.Day})
+		}
+		for i := 1; i < len(got); i++ {
+			if got[i-1][0] > got[i][0] || got[i-1][0] == got[i][0] && got[i-1][1] >= got[i][1] {
+				t.Errorf("solvers not ordered by year and day: %v", got)
+			}
+		}
+	})
+	t.Run("list the solvers of a year", func(t *testing.T) {
+		aoc.Register(aoc.Solver{Year: 2097, Day: 2, Part1: constant(1), Part2: constant(2)})
+		aoc.Register(aoc.Solver{Year: 2097, Day: 1, Part1: constant(1), Part2: constant(2)})
+		var got []int
+		for _, solver := range aoc.YearSolvers(2097) {
+			got = append(got, solver.Day)
+		}
+		if !reflect.DeepEqual(got, []int{1, 2}) {
+			t.Errorf("got: %v, want: %v", got, []int{1, 2})
+		}
+	})
+	t.Run("fail on duplicate day", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("did not panic as expected")
+			}
+		}()
+		aoc.Register(aoc.Solver{Year: 2099, Day: 103, Part1: constant(1), Part2: constant(2)})
+		aoc.Register(aoc.Solver{Year: 2099, Day: 103, Part1: constant(1), Part2: constant(2)})
+	})
+	t.Run("fail to look up missing day", func(t *testing.T) {
+		if _, ok := aoc.Lookup(2099, 999); ok {
+			t.Error("found solver for unregistered day")
+		}
+		if _, ok := aoc.Lookup(2096, 101); ok {
+			t.Error("found solver for unregistered year")
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package aoc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/dugword/advent-of-code-2021/internal/logging"
+)
+
+// Values records the intermediate values a solver computes on the way to its
+// answer, such as the gamma and epsilon rates behind a power consumption.
+type Values struct {
+	mu     sync.Mutex
+	values map[string]int
+}
+
+type valuesKey struct{}
+
+// WithValues returns a context whose solvers record their intermediate values
+// in the returned Values.
+func WithValues(ctx context.Context) (context.Context, *Values) {
+	values := &Values{values: map[string]int{}}
+	return context.WithValue(ctx, valuesKey{}, values), values
+}
+
+// Record an intermediate value if ctx came from WithValues, and log it at
+// debug level.
+func Record(ctx context.Context, name string, value int) {
+	logging.FromContext(ctx).DebugContext(ctx, "value", "name", name, "value", value)
+	values, ok := ctx.Value(valuesKey{}).(*Values)
+	if !ok {
+		return
+	}
+	values.mu.Lock()
+	defer values.mu.Unlock()
+	values.values[name] = value
+}
+
+// Map returns a copy of the values recorded so far.
+func (v *Values) Map() map[string]int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	values := make(map[string]int, len(v.values))
+	for name, value := range v.values {
+		values[name] = value
+	}
+	return values
+}
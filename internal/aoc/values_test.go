@@ -0,0 +1,25 @@
+package aoc_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+)
+
+func TestValues(t *testing.T) {
+	t.Run("record values", func(t *testing.T) {
+		ctx, values := aoc.WithValues(context.Background())
+		aoc.Record(ctx, "gamma", 22)
+		aoc.Record(ctx, "epsilon", 9)
+		aoc.Record(ctx, "gamma", 23)
+		want := map[string]int{"gamma": 23, "epsilon": 9}
+		if got := values.Map(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got: %v, want: %v", got, want)
+		}
+	})
+	t.Run("ignore values without a recorder", func(t *testing.T) {
+		aoc.Record(context.Background(), "gamma", 22)
+	})
+}
@@ -0,0 +1,188 @@
+// Package aoctest provides the helpers shared by the tests of every day.
+package aoctest
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// RunFunc runs the command for a day, like the Run of each day.
+type RunFunc func(args []string, stdout, stderr io.Writer) error
+
+// Run calls run with args and returns what it wrote to stdout, failing the
+// test if it returns an error.
+func Run(t testing.TB, run RunFunc, args ...string) string {
+	t.Helper()
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if err := run(args, &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	return stdout.String()
+}
+
+// RunStderr calls run with args like Run, and returns what it wrote to stdout
+// and to stderr.
+func RunStderr(t testing.TB, run RunFunc, args ...string) (stdout, stderr string) {
+	t.Helper()
+	var stdoutBuffer bytes.Buffer
+	var stderrBuffer bytes.Buffer
+	if err := run(args, &stdoutBuffer, &stderrBuffer); err != nil {
+		t.Fatal(err)
+	}
+	return stdoutBuffer.String(), stderrBuffer.String()
+}
+
+// RunError calls run with args and returns its error, failing the test if it
+// does not return one.
+func RunError(t testing.TB, run RunFunc, args ...string) error {
+	t.Helper()
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	err := run(args, &stdout, &stderr)
+	if err == nil {
+		t.Fatal("did not fail as expected")
+	}
+	return err
+}
+
+// AssertError fails the test unless the message of got is want.
+func AssertError(t testing.TB, got error, want string) {
+	t.Helper()
+	if got == nil {
+		t.Fatal("did not fail as expected")
+	}
+	if got.Error() != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+}
+
+// AssertErrorPrefix fails the test unless the message of got starts with
+// want.
+func AssertErrorPrefix(t testing.TB, got error, want string) {
+	t.Helper()
+	if got == nil {
+		t.Fatal("did not fail as expected")
+	}
+	if !strings.HasPrefix(got.Error(), want) {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+}
+
+// AssertExitCode fails the test unless got exits a command with want.
+func AssertExitCode(t testing.TB, got error, want int) {
+	t.Helper()
+	if code := aoc.ExitCode(got); code != want {
+		t.Errorf("got exit code: %d, want: %d, for error: %v", code, want, got)
+	}
+}
+
+// LoaderRejects fails the test unless load fails on both a missing file and
+// ./testdata/invalid.
+func LoaderRejects[T any](t *testing.T, load func(filepath string) (T, error)) {
+	t.Helper()
+	t.Run("fail on missing file", func(t *testing.T) {
+		if _, got := load("./testdata/missing"); got == nil {
+			t.Error("did not fail as expected")
+		}
+	})
+	t.Run("fail on invalid file", func(t *testing.T) {
+		if _, got := load("./testdata/invalid"); got == nil {
+			t.Error("did not fail as expected")
+		}
+	})
+}
+
+// Golden fails the test unless got matches the contents of the golden file at
+// path. Running the tests with -update rewrites the file with got instead.
+func Golden(t testing.TB, path string, got string) {
+	t.Helper()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v, run with -update to create it", err)
+		return
+	}
+	if got != string(want) {
+		t.Errorf("%s: got: %q, want: %q", path, got, want)
+	}
+}
+
+// Example fails the test unless the solver gives the answers to the example
+// embedded in its Example.
+func Example(t testing.TB, solver aoc.Solver) {
+	t.Helper()
+	for part := 1; part <= 2; part++ {
+		if err := aoc.CheckExample(context.Background(), solver, part); err != nil {
+			t.Errorf("got: %v, want: the answers to the example", err)
+		}
+	}
+}
+
+// Cases runs both parts of the solver on every ./testdata/cases/<name>.in
+// and checks the answers against <name>.part1.golden and <name>.part2.golden.
+// A part that fails is golden as "error: " followed by its message.
+func Cases(t *testing.T, solver aoc.Solver) {
+	t.Helper()
+	inputs, err := filepath.Glob("./testdata/cases/*.in")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("no cases in ./testdata/cases")
+	}
+	for _, input := range inputs {
+		input := input
+		name := strings.TrimSuffix(input, ".in")
+		t.Run(filepath.Base(name), func(t *testing.T) {
+			contents, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for part := 1; part <= 2; part++ {
+				got := solveCase(solver.Part(part), contents)
+				Golden(t, fmt.Sprintf("%s.part%d.golden", name, part), got)
+			}
+		})
+	}
+}
+
+func solveCase(solve aoc.SolveFunc, input []byte) string {
+	answer, err := solve(context.Background(), bytes.NewReader(input))
+	if err != nil {
+		return fmt.Sprintf("error: %v\n", err)
+	}
+	return fmt.Sprintf("%d\n", answer)
+}
+
+// AddSeedCorpus adds ./testdata/input and ./testdata/invalid to the seed
+// corpus of a fuzz target.
+func AddSeedCorpus(f *testing.F) {
+	f.Helper()
+	for _, path := range []string{"./testdata/input", "./testdata/invalid"} {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(contents)
+	}
+}
@@ -0,0 +1,176 @@
+package aoctest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/aoctest"
+)
+
+// recorder captures the failures reported by a helper.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatal(args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprint(args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func echo(args []string, stdout, stderr io.Writer) error {
+	if len(args) < 2 {
+		return errors.New("must provide a word")
+	}
+	fmt.Fprintln(stdout, args[1])
+	fmt.Fprintln(stderr, "echoed", args[1])
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	t.Run("return stdout", func(t *testing.T) {
+		if got := aoctest.Run(t, echo, "echo", "hello"); got != "hello\n" {
+			t.Errorf("got: %q, want: %q", got, "hello\n")
+		}
+	})
+	t.Run("fail on error", func(t *testing.T) {
+		r := &recorder{TB: t}
+		aoctest.Run(r, echo, "echo")
+		if len(r.failures) != 1 {
+			t.Errorf("got failures: %q", r.failures)
+		}
+	})
+}
+
+func TestRunStderr(t *testing.T) {
+	t.Run("return stdout and stderr", func(t *testing.T) {
+		stdout, stderr := aoctest.RunStderr(t, echo, "echo", "hello")
+		if stdout != "hello\n" {
+			t.Errorf("got: %q, want: %q", stdout, "hello\n")
+		}
+		if stderr != "echoed hello\n" {
+			t.Errorf("got: %q, want: %q", stderr, "echoed hello\n")
+		}
+	})
+	t.Run("fail on error", func(t *testing.T) {
+		r := &recorder{TB: t}
+		aoctest.RunStderr(r, echo, "echo")
+		if len(r.failures) != 1 {
+			t.Errorf("got failures: %q", r.failures)
+		}
+	})
+}
+
+func TestRunError(t *testing.T) {
+	t.Run("return the error", func(t *testing.T) {
+		got := aoctest.RunError(t, echo, "echo")
+		aoctest.AssertError(t, got, "must provide a word")
+		aoctest.AssertErrorPrefix(t, got, "must provide")
+	})
+	t.Run("fail without error", func(t *testing.T) {
+		r := &recorder{TB: t}
+		aoctest.RunError(r, echo, "echo", "hello")
+		if len(r.failures) != 1 {
+			t.Errorf("got failures: %q", r.failures)
+		}
+	})
+}
+
+func TestAssertError(t *testing.T) {
+	r := &recorder{TB: t}
+	aoctest.AssertError(r, errors.New("broken"), "fixed")
+	aoctest.AssertErrorPrefix(r, errors.New("broken"), "fixed")
+	want := []string{
+		`got: "broken", want: "fixed"`,
+		`got: "broken", want: "fixed"`,
+	}
+	if strings.Join(r.failures, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got failures: %q, want: %q", r.failures, want)
+	}
+}
+
+func TestLoaderRejects(t *testing.T) {
+	load := func(filepath string) ([]int, error) {
+		return nil, errors.New("invalid")
+	}
+	aoctest.LoaderRejects(t, load)
+}
+
+func TestGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output.golden")
+	if err := os.WriteFile(path, []byte("42\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("match golden file", func(t *testing.T) {
+		aoctest.Golden(t, path, "42\n")
+	})
+	t.Run("fail on mismatch", func(t *testing.T) {
+		r := &recorder{TB: t}
+		aoctest.Golden(r, path, "43\n")
+		if len(r.failures) != 1 {
+			t.Errorf("got failures: %q", r.failures)
+		}
+	})
+	t.Run("fail on missing golden file", func(t *testing.T) {
+		r := &recorder{TB: t}
+		aoctest.Golden(r, path+".missing", "42\n")
+		if len(r.failures) != 1 || !strings.HasSuffix(r.failures[0], "run with -update to create it") {
+			t.Errorf("got failures: %q", r.failures)
+		}
+	})
+}
+
+func TestExample(t *testing.T) {
+	count := func(_ context.Context, input io.Reader) (int, error) {
+		contents, err := io.ReadAll(input)
+		return strings.Count(string(contents), "\n"), err
+	}
+	double := func(ctx context.Context, input io.Reader) (int, error) {
+		n, err := count(ctx, input)
+		return 2 * n, err
+	}
+	example := fstest.MapFS{
+		"example/input":  {Data: []byte("a\nb\nc\n")},
+		"example/part_1": {Data: []byte("3\n")},
+		"example/part_2": {Data: []byte("6\n")},
+	}
+	t.Run("pass on the answers", func(t *testing.T) {
+		aoctest.Example(t, aoc.Solver{Day: 1, Part1: count, Part2: double, Example: example})
+	})
+	t.Run("fail on a wrong answer", func(t *testing.T) {
+		r := &recorder{TB: t}
+		aoctest.Example(r, aoc.Solver{Day: 1, Part1: count, Part2: count, Example: example})
+		if len(r.failures) != 1 {
+			t.Errorf("got failures: %q", r.failures)
+		}
+	})
+}
+
+func TestCases(t *testing.T) {
+	count := func(_ context.Context, input io.Reader) (int, error) {
+		contents, err := io.ReadAll(input)
+		return strings.Count(string(contents), "\n"), err
+	}
+	fail := func(_ context.Context, input io.Reader) (int, error) {
+		return 0, errors.New("broken")
+	}
+	solver := aoc.Solver{Day: 1, Part1: count, Part2: fail}
+	aoctest.Cases(t, solver)
+}
@@ -0,0 +1,132 @@
+// Package bittrie counts values by the prefixes of their bits, to filter
+// them by a bit at a time without scanning them again.
+package bittrie
+
+// BitTrie of the low bits of values, from the most significant of width bits.
+type BitTrie struct {
+	width int
+	root  node
+}
+
+type node struct {
+	// count of the values under the node.
+	count int
+	// first value inserted under a leaf.
+	first    int
+	children [2]*node
+}
+
+// New BitTrie of values of width bits.
+func New(width int) *BitTrie {
+	return &BitTrie{width: max(width, 0)}
+}
+
+// Width of the values in bits.
+func (t *BitTrie) Width() int {
+	return t.width
+}
+
+// Len is the number of values inserted.
+func (t *BitTrie) Len() int {
+	return t.root.count
+}
+
+// Insert a value, ignoring its bits above the width.
+func (t *BitTrie) Insert(value int) {
+	n := &t.root
+	n.count++
+	for depth := 0; depth < t.width; depth++ {
+		bit := t.bit(value, depth)
+		if n.children[bit] == nil {
+			n.children[bit] = &node{}
+		}
+		n = n.children[bit]
+		n.count++
+	}
+	if n.count == 1 {
+		n.first = value
+	}
+}
+
+// CountPrefix counts the values whose most significant length bits are the
+// low length bits of prefix, or 0 if length is more than the width.
+func (t *BitTrie) CountPrefix(prefix, length int) int {
+	if length < 0 || length > t.width {
+		return 0
+	}
+	n := &t.root
+	for depth := 0; depth < length; depth++ {
+		n = n.children[uint64(prefix)>>(length-1-depth)&1]
+		if n == nil {
+			return 0
+		}
+	}
+	return n.count
+}
+
+// Walk from the most significant bit to a leaf, following the only child
+// where there is one, and otherwise the bit chosen from the number of values
+// with a zero and a one after the depth bits walked so far. It returns the
+// first value inserted under the leaf, or false if there are none.
+func (t *BitTrie) Walk(choose func(depth, zeros, ones int) int) (int, bool) {
+	if t.root.count == 0 {
+		return 0, false
+	}
+	n := &t.root
+	for depth := 0; depth < t.width; depth++ {
+		zeros, ones := n.children[0].size(), n.children[1].size()
+		switch {
+		case zeros == 0:
+			n = n.children[1]
+		case ones == 0:
+			n = n.children[0]
+		default:
+			n = n.children[choose(depth, zeros, ones)&1]
+		}
+	}
+	return n.first, true
+}
+
+// MostCommon walks to the child with the most values, or to tie when both
+// have as many.
+func (t *BitTrie) MostCommon(tie int) (int, bool) {
+	return t.Walk(func(depth, zeros, ones int) int {
+		switch {
+		case ones > zeros:
+			return 1
+		case zeros > ones:
+			return 0
+		}
+		return tie
+	})
+}
+
+// LeastCommon walks to the child with the fewest values, or to tie when both
+// have as many.
+func (t *BitTrie) LeastCommon(tie int) (int, bool) {
+	return t.Walk(func(depth, zeros, ones int) int {
+		switch {
+		case ones < zeros:
+			return 1
+		case zeros < ones:
+			return 0
+		}
+		return tie
+	})
+}
+
+// bit of a value at a depth from its most significant bit.
+func (t *BitTrie) bit(value, depth int) uint64 {
+	shift := t.width - 1 - depth
+	if shift >= 64 {
+		return 0
+	}
+	return uint64(value) >> shift & 1
+}
+
+func (n *node) size() int {
+	if n == nil {
+		return 0
+	}
+	return n.count
+}
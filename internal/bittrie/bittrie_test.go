@@ -0,0 +1,83 @@
+package bittrie_test
+
+import (
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/bittrie"
+)
+
+// newTrie of the example diagnostics from the puzzle description.
+func newTrie() *bittrie.BitTrie {
+	trie := bittrie.New(5)
+	for _, value := range []int{0b00100, 0b11110, 0b10110, 0b10111, 0b10101, 0b01111, 0b00111, 0b11100, 0b10000, 0b11001, 0b00010, 0b01010} {
+		trie.Insert(value)
+	}
+	return trie
+}
+
+func TestCountPrefix(t *testing.T) {
+	trie := newTrie()
+	testCases := []struct {
+		name   string
+		prefix int
+		length int
+		want   int
+	}{
+		{name: "everything", prefix: 0, length: 0, want: 12},
+		{name: "one bit", prefix: 0b1, length: 1, want: 7},
+		{name: "two bits", prefix: 0b10, length: 2, want: 4},
+		{name: "missing", prefix: 0b0110, length: 4, want: 0},
+		{name: "every bit", prefix: 0b10110, length: 5, want: 1},
+		{name: "longer than the width", prefix: 0b101100, length: 6, want: 0},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := trie.CountPrefix(testCase.prefix, testCase.length); got != testCase.want {
+				t.Errorf("got: %v, want: %v", got, testCase.want)
+			}
+		})
+	}
+	if got := trie.Len(); got != 12 {
+		t.Errorf("got: %v, want: %v", got, 12)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	trie := newTrie()
+	testCases := []struct {
+		name string
+		walk func() (int, bool)
+		want int
+	}{
+		{name: "most common", walk: func() (int, bool) { return trie.MostCommon(1) }, want: 0b10111},
+		{name: "least common", walk: func() (int, bool) { return trie.LeastCommon(0) }, want: 0b01010},
+		{name: "always zero", walk: func() (int, bool) {
+			return trie.Walk(func(depth, zeros, ones int) int { return 0 })
+		}, want: 0b00010},
+		{name: "by depth", walk: func() (int, bool) {
+			return trie.Walk(func(depth, zeros, ones int) int { return depth % 2 })
+		}, want: 0b01010},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, ok := testCase.walk()
+			if !ok || got != testCase.want {
+				t.Errorf("got: %05b %v, want: %05b", got, ok, testCase.want)
+			}
+		})
+	}
+	t.Run("walk nothing", func(t *testing.T) {
+		if _, ok := bittrie.New(5).MostCommon(1); ok {
+			t.Error("got a value from an empty trie")
+		}
+	})
+	t.Run("return the first value of a leaf", func(t *testing.T) {
+		trie := bittrie.New(2)
+		for _, value := range []int{0b111, 0b011, 0b001} {
+			trie.Insert(value)
+		}
+		if got, _ := trie.MostCommon(0); got != 0b111 {
+			t.Errorf("got: %03b, want: %03b", got, 0b111)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+// Package cache stores the answers of aoc run on disk, keyed by the build of
+// the solvers, the input and the part, so that a part whose code and input have
+// not changed is not solved again.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// entry is the file cached for one part.
+type entry struct {
+	BuildID   string `json:"build_id"`
+	Year      int    `json:"year"`
+	Day       int    `json:"day"`
+	Part      int    `json:"part"`
+	InputHash string `json:"input_hash"`
+	Answer    int    `json:"answer"`
+}
+
+// Cache of the answers of one build in a directory.
+type Cache struct {
+	dir     string
+	buildID string
+}
+
+// New returns the cache of the answers of the build with buildID in dir.
+func New(dir, buildID string) *Cache {
+	return &Cache{dir: dir, buildID: buildID}
+}
+
+// Get the answer cached for a part and input.
+func (c *Cache) Get(year, day, part int, inputHash string) (int, bool) {
+	want := entry{BuildID: c.buildID, Year: year, Day: day, Part: part, InputHash: inputHash}
+	contents, err := os.ReadFile(c.path(want))
+	if err != nil {
+		return 0, false
+	}
+	var got entry
+	if err := json.Unmarshal(contents, &got); err != nil {
+		return 0, false
+	}
+	want.Answer = got.Answer
+	if got != want {
+		return 0, false
+	}
+	return got.Answer, true
+}
+
+// Put the answer of a part and input in the cache.
+func (c *Cache) Put(year, day, part int, inputHash string, answer int) error {
+	e := entry{BuildID: c.buildID, Year: year, Day: day, Part: part, InputHash: inputHash, Answer: answer}
+	contents, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	// Write to a temporary file first so that parts solved at the same
+	// time never read a partial entry.
+	file, err := os.CreateTemp(c.dir, "entry-*")
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(contents); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), c.path(e))
+}
+
+// path of the entry for the key fields of e.
+func (c *Cache) path(e entry) string {
+	key := sha256.Sum256([]byte(fmt.Sprintf("%s\n%d\n%d\n%d\n%s", e.BuildID, e.Year, e.Day, e.Part, e.InputHash)))
+	return filepath.Join(c.dir, hex.EncodeToString(key[:])+".json")
+}
+
+// Clean removes every entry of the cache in dir, and dir itself if nothing
+// else is left in it, returning the number of entries removed. Other files in
+// dir are kept, and a missing dir is already clean.
+func Clean(dir string) (int, error) {
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, file := range files {
+		name := file.Name()
+		isEntry := filepath.Ext(name) == ".json" && len(name) == sha256.Size*2+len(".json")
+		isTemp := strings.HasPrefix(name, "entry-")
+		if !file.Type().IsRegular() || !isEntry && !isTemp {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return removed, err
+		}
+		if isEntry {
+			removed++
+		}
+	}
+	// Fails if dir holds anything else, which is left alone.
+	os.Remove(dir)
+	return removed, nil
+}
+
+// BuildID identifies the build of the running binary by the SHA-256 of its
+// executable, which changes whenever the code of any solver does.
+func BuildID() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
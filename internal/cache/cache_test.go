@@ -0,0 +1,103 @@
+package cache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/cache"
+)
+
+func TestCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "answers")
+	c := cache.New(dir, "build-1")
+	t.Run("miss before put", func(t *testing.T) {
+		if _, ok := c.Get(2021, 3, 1, "abc"); ok {
+			t.Error("got a cached answer")
+		}
+	})
+	if err := c.Put(2021, 3, 1, "abc", 198); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("hit after put", func(t *testing.T) {
+		got, ok := c.Get(2021, 3, 1, "abc")
+		if !ok || got != 198 {
+			t.Errorf("got: %d %v, want: %d", got, ok, 198)
+		}
+	})
+	testCases := []struct {
+		name      string
+		cache     *cache.Cache
+		part      int
+		inputHash string
+	}{
+		{name: "miss another build", cache: cache.New(dir, "build-2"), part: 1, inputHash: "abc"},
+		{name: "miss another part", cache: c, part: 2, inputHash: "abc"},
+		{name: "miss another input", cache: c, part: 1, inputHash: "abd"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if _, ok := testCase.cache.Get(2021, 3, testCase.part, testCase.inputHash); ok {
+				t.Error("got a cached answer")
+			}
+		})
+	}
+}
+
+func TestClean(t *testing.T) {
+	t.Run("remove the entries and the directory", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "answers")
+		c := cache.New(dir, "build")
+		for part := 1; part <= 2; part++ {
+			if err := c.Put(2021, 1, part, "abc", part); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got, err := cache.Clean(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != 2 {
+			t.Errorf("got: %d, want: %d", got, 2)
+		}
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("got: %v, want directory removed", err)
+		}
+	})
+	t.Run("keep other files", func(t *testing.T) {
+		dir := t.TempDir()
+		other := filepath.Join(dir, "notes.txt")
+		if err := os.WriteFile(other, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := cache.New(dir, "build").Put(2021, 1, 1, "abc", 1); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cache.Clean(dir); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(other); err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("clean a missing directory", func(t *testing.T) {
+		got, err := cache.Clean(filepath.Join(t.TempDir(), "missing"))
+		if err != nil || got != 0 {
+			t.Errorf("got: %d %v, want: 0", got, err)
+		}
+	})
+}
+
+func TestBuildID(t *testing.T) {
+	first, err := cache.BuildID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := cache.BuildID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == "" || first != second {
+		t.Errorf("got: %q and %q, want the same build ID", first, second)
+	}
+}
@@ -0,0 +1,372 @@
+// Package config loads the settings of the aoc command from a config file and
+// AOC_* environment variables. Flags override the environment, which overrides
+// the file, which overrides the defaults.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// FileName of the config file looked for in the working directory.
+const FileName = ".aoc.json"
+
+// Sources of a setting, from lowest to highest precedence.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// Formats of the answers written by the aoc command.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Config of the aoc command.
+type Config struct {
+	// Year of the puzzles.
+	Year int
+	// InputDir contains a YYYY/dayNN/input file per day of each year.
+	InputDir string
+	// SolutionsDir is where each answer is written in a directory per year,
+	// or empty to not write them.
+	SolutionsDir string
+	// SessionFile holds the session token of the Advent of Code website.
+	SessionFile string
+	// Format of the answers, FormatText or FormatJSON.
+	Format string
+	// Workers is the number of parts solved at once.
+	Workers int
+	// HistoryFile is the JSON lines file a record of every part solved by the
+	// run command is appended to, or empty to not record them.
+	HistoryFile string
+	// CacheDir is where the run command caches answers, or empty to not
+	// cache them.
+	CacheDir string
+	// File the config was loaded from, or empty if there was none.
+	File string
+	// Sources of each setting, keyed by its name in the config file.
+	Sources map[string]string
+}
+
+// file is the config file, where a missing setting is nil.
+type file struct {
+	Year         *int    `json:"year"`
+	InputDir     *string `json:"input_dir"`
+	SolutionsDir *string `json:"solutions_dir"`
+	SessionFile  *string `json:"session_file"`
+	Format       *string `json:"format"`
+	Workers      *int    `json:"workers"`
+	HistoryFile  *string `json:"history_file"`
+	CacheDir     *string `json:"cache_dir"`
+}
+
+// Default returns the config used when nothing else is set.
+func Default() Config {
+	sessionFile := ""
+	if dir, err := os.UserConfigDir(); err == nil {
+		sessionFile = filepath.Join(dir, "aoc", "session")
+	}
+	cacheDir := ""
+	if dir, err := os.UserCacheDir(); err == nil {
+		cacheDir = filepath.Join(dir, "aoc", "answers")
+	}
+	return Config{
+		Year:         2021,
+		InputDir:     "./years",
+		SolutionsDir: "",
+		SessionFile:  sessionFile,
+		Format:       FormatText,
+		Workers:      runtime.NumCPU(),
+		HistoryFile:  filepath.Join(".aoc", "history.jsonl"),
+		CacheDir:     cacheDir,
+		Sources: map[string]string{
+			"year":          SourceDefault,
+			"input_dir":     SourceDefault,
+			"solutions_dir": SourceDefault,
+			"session_file":  SourceDefault,
+			"format":        SourceDefault,
+			"workers":       SourceDefault,
+			"history_file":  SourceDefault,
+			"cache_dir":     SourceDefault,
+		},
+	}
+}
+
+// Load the config from the defaults, then the config file, then the
+// environment. The config file is the one named by AOC_CONFIG, or else
+// .aoc.json in the working directory, or else aoc/config.json in the user's
+// config directory.
+func Load() (Config, error) {
+	config := Default()
+	path, err := findFile()
+	if err != nil {
+		return Config{}, err
+	}
+	if path != "" {
+		if err := config.loadFile(path); err != nil {
+			return Config{}, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	}
+	if err := config.loadEnv(); err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+// findFile returns the path of the config file, or empty if there is none.
+func findFile() (string, error) {
+	if path := os.Getenv("AOC_CONFIG"); path != "" {
+		return path, nil
+	}
+	paths := []string{FileName}
+	if dir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, "aoc", "config.json"))
+	}
+	for _, path := range paths {
+		_, err := os.Stat(path)
+		if err == nil {
+			return path, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+func (c *Config) loadFile(path string) error {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.DisallowUnknownFields()
+	var f file
+	if err := decoder.Decode(&f); err != nil {
+		return err
+	}
+	c.File = path
+	if f.Year != nil {
+		c.Year = *f.Year
+		c.Sources["year"] = SourceFile
+	}
+	if f.InputDir != nil {
+		c.InputDir = *f.InputDir
+		c.Sources["input_dir"] = SourceFile
+	}
+	if f.SolutionsDir != nil {
+		c.SolutionsDir = *f.SolutionsDir
+		c.Sources["solutions_dir"] = SourceFile
+	}
+	if f.SessionFile != nil {
+		c.SessionFile = *f.SessionFile
+		c.Sources["session_file"] = SourceFile
+	}
+	if f.Format != nil {
+		c.Format = *f.Format
+		c.Sources["format"] = SourceFile
+	}
+	if f.Workers != nil {
+		c.Workers = *f.Workers
+		c.Sources["workers"] = SourceFile
+	}
+	if f.HistoryFile != nil {
+		c.HistoryFile = *f.HistoryFile
+		c.Sources["history_file"] = SourceFile
+	}
+	if f.CacheDir != nil {
+		c.CacheDir = *f.CacheDir
+		c.Sources["cache_dir"] = SourceFile
+	}
+	return c.Validate()
+}
+
+func (c *Config) loadEnv() error {
+	for _, v := range []struct {
+		name  string
+		value flag.Value
+	}{
+		{"year", (*intValue)(&c.Year)},
+		{"input_dir", (*stringValue)(&c.InputDir)},
+		{"solutions_dir", (*stringValue)(&c.SolutionsDir)},
+		{"session_file", (*stringValue)(&c.SessionFile)},
+		{"format", (*stringValue)(&c.Format)},
+		{"workers", (*intValue)(&c.Workers)},
+		{"history_file", (*stringValue)(&c.HistoryFile)},
+		{"cache_dir", (*stringValue)(&c.CacheDir)},
+	} {
+		key := EnvKey(v.name)
+		value, ok := os.LookupEnv(key)
+		if !ok {
+			continue
+		}
+		if err := v.value.Set(value); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		c.Sources[v.name] = SourceEnv
+	}
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("invalid environment: %w", err)
+	}
+	return nil
+}
+
+// Validate the settings, such as after parsing the flags of Register.
+func (c *Config) Validate() error {
+	switch {
+	case c.Year < 2015:
+		return fmt.Errorf("year %d is before the first Advent of Code", c.Year)
+	case c.Format != FormatText && c.Format != FormatJSON:
+		return fmt.Errorf("format must be %s or %s, not %q", FormatText, FormatJSON, c.Format)
+	case c.Workers < 1:
+		return errors.New("workers must be positive")
+	}
+	return nil
+}
+
+// EnvKey is the environment variable of a setting.
+func EnvKey(name string) string {
+	return "AOC_" + strings.ToUpper(name)
+}
+
+// RegisterYear registers a flag overriding the year, defaulting to its current
+// value.
+func (c *Config) RegisterYear(flags *flag.FlagSet) {
+	flags.Var(c.flagValue("year", (*intValue)(&c.Year)), "year", "`year` of the puzzles")
+}
+
+// RegisterInputDir registers a flag overriding the input directory,
+// defaulting to its current value.
+func (c *Config) RegisterInputDir(flags *flag.FlagSet) {
+	flags.Var(c.flagValue("input_dir", (*stringValue)(&c.InputDir)), "inputs", "`directory` containing a YYYY/dayNN/input file per day")
+}
+
+// RegisterHistoryFile registers a flag overriding the history file,
+// defaulting to its current value.
+func (c *Config) RegisterHistoryFile(flags *flag.FlagSet) {
+	flags.Var(c.flagValue("history_file", (*stringValue)(&c.HistoryFile)), "history", "JSON lines `file` recording every part solved, empty to not record them")
+}
+
+// RegisterCacheDir registers a flag overriding the cache directory,
+// defaulting to its current value.
+func (c *Config) RegisterCacheDir(flags *flag.FlagSet) {
+	flags.Var(c.flagValue("cache_dir", (*stringValue)(&c.CacheDir)), "cache", "`directory` caching the answers, empty to not cache them")
+}
+
+// Register flags overriding the year, input directory, solutions directory,
+// workers, history file and cache directory, defaulting to their current
+// values.
+func (c *Config) Register(flags *flag.FlagSet) {
+	c.RegisterYear(flags)
+	c.RegisterInputDir(flags)
+	flags.Var(c.flagValue("solutions_dir", (*stringValue)(&c.SolutionsDir)), "solutions", "`directory` to write each answer to")
+	flags.Var(c.flagValue("workers", (*intValue)(&c.Workers)), "workers", "`number` of parts solved in parallel")
+	c.RegisterHistoryFile(flags)
+	c.RegisterCacheDir(flags)
+}
+
+// InputFilepath returns the path to the input file for a day of a year.
+func (c Config) InputFilepath(year, day int) string {
+	return filepath.Join(c.InputDir, strconv.Itoa(year), fmt.Sprintf("day%02d", day), "input")
+}
+
+// YearSolutionsDir returns the directory of the answers for a year.
+func (c Config) YearSolutionsDir(year int) string {
+	return filepath.Join(c.SolutionsDir, strconv.Itoa(year))
+}
+
+// WriteTable writes each setting with its value, source and environment
+// variable as a table.
+func (c Config) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	file := c.File
+	if file == "" {
+		file = "none"
+	}
+	fmt.Fprintf(tw, "config file: %s\n\n", file)
+	fmt.Fprintln(tw, "SETTING\tVALUE\tSOURCE\tENV")
+	for _, setting := range []struct {
+		name  string
+		value interface{}
+	}{
+		{"year", c.Year},
+		{"input_dir", c.InputDir},
+		{"solutions_dir", c.SolutionsDir},
+		{"session_file", c.SessionFile},
+		{"format", c.Format},
+		{"workers", c.Workers},
+		{"history_file", c.HistoryFile},
+		{"cache_dir", c.CacheDir},
+	} {
+		fmt.Fprintf(tw, "%s\t%v\t%s\t%s\n", setting.name, setting.value, c.Sources[setting.name], EnvKey(setting.name))
+	}
+	return tw.Flush()
+}
+
+// flagValue marks a setting as set by a flag whenever the flag is set.
+func (c *Config) flagValue(name string, value flag.Value) flag.Value {
+	return &sourcedValue{Value: value, mark: func() {
+		c.Sources[name] = SourceFlag
+	}}
+}
+
+type sourcedValue struct {
+	flag.Value
+	mark func()
+}
+
+func (v *sourcedValue) String() string {
+	if v.Value == nil {
+		return ""
+	}
+	return v.Value.String()
+}
+
+func (v *sourcedValue) Set(s string) error {
+	if err := v.Value.Set(s); err != nil {
+		return err
+	}
+	v.mark()
+	return nil
+}
+
+type stringValue string
+
+func (v *stringValue) Set(s string) error {
+	*v = stringValue(s)
+	return nil
+}
+
+func (v *stringValue) String() string {
+	return string(*v)
+}
+
+type intValue int
+
+func (v *intValue) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	*v = intValue(n)
+	return nil
+}
+
+func (v *intValue) String() string {
+	return strconv.Itoa(int(*v))
+}
@@ -0,0 +1,138 @@
+package config_test
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/config"
+)
+
+// writeConfig writes a config file and points AOC_CONFIG at it.
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AOC_CONFIG", path)
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("load defaults without a config file", func(t *testing.T) {
+		t.Setenv("AOC_CONFIG", "")
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+		t.Setenv("HOME", t.TempDir())
+		got, err := config.Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := config.Default()
+		if got.InputDir != want.InputDir || got.Year != want.Year || got.File != "" {
+			t.Errorf("got: %+v, want: %+v", got, want)
+		}
+	})
+	t.Run("override defaults with the file and the file with env", func(t *testing.T) {
+		path := writeConfig(t, `{"year": 2022, "input_dir": "/inputs", "workers": 2}`)
+		t.Setenv("AOC_WORKERS", "3")
+		got, err := config.Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.File != path {
+			t.Errorf("got file: %q, want: %q", got.File, path)
+		}
+		if got.Year != 2022 || got.InputDir != "/inputs" || got.Workers != 3 || got.Format != config.FormatText {
+			t.Errorf("got: %+v", got)
+		}
+		wantSources := map[string]string{
+			"year":      config.SourceFile,
+			"input_dir": config.SourceFile,
+			"workers":   config.SourceEnv,
+			"format":    config.SourceDefault,
+		}
+		for name, want := range wantSources {
+			if got := got.Sources[name]; got != want {
+				t.Errorf("%s got source: %q, want: %q", name, got, want)
+			}
+		}
+	})
+	t.Run("override env with flags", func(t *testing.T) {
+		writeConfig(t, `{"input_dir": "/inputs"}`)
+		t.Setenv("AOC_SOLUTIONS_DIR", "/env")
+		got, err := config.Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		flags := flag.NewFlagSet("test", flag.ContinueOnError)
+		flags.SetOutput(io.Discard)
+		got.Register(flags)
+		if err := flags.Parse([]string{"-solutions", "/flag"}); err != nil {
+			t.Fatal(err)
+		}
+		if got.InputDir != "/inputs" || got.SolutionsDir != "/flag" {
+			t.Errorf("got: %+v", got)
+		}
+		if got.Sources["solutions_dir"] != config.SourceFlag {
+			t.Errorf("got source: %q, want: %q", got.Sources["solutions_dir"], config.SourceFlag)
+		}
+	})
+	t.Run("disable the history with an empty env", func(t *testing.T) {
+		writeConfig(t, `{"history_file": "/history.jsonl"}`)
+		t.Setenv("AOC_HISTORY_FILE", "")
+		got, err := config.Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.HistoryFile != "" || got.Sources["history_file"] != config.SourceEnv {
+			t.Errorf("got: %q from %s, want none from env", got.HistoryFile, got.Sources["history_file"])
+		}
+	})
+	t.Run("fail on unknown setting", func(t *testing.T) {
+		writeConfig(t, `{"inputs": "/inputs"}`)
+		_, got := config.Load()
+		if got == nil || !strings.Contains(got.Error(), `unknown field "inputs"`) {
+			t.Errorf("got: %v, want an unknown field error", got)
+		}
+	})
+	t.Run("fail on invalid format", func(t *testing.T) {
+		writeConfig(t, `{}`)
+		t.Setenv("AOC_FORMAT", "yaml")
+		want := `invalid environment: format must be text or json, not "yaml"`
+		if _, got := config.Load(); got == nil || got.Error() != want {
+			t.Errorf("got: %v, want: %q", got, want)
+		}
+	})
+	t.Run("fail on invalid workers", func(t *testing.T) {
+		writeConfig(t, `{}`)
+		t.Setenv("AOC_WORKERS", "many")
+		want := `invalid AOC_WORKERS: strconv.Atoi: parsing "many": invalid syntax`
+		if _, got := config.Load(); got == nil || got.Error() != want {
+			t.Errorf("got: %v, want: %q", got, want)
+		}
+	})
+	t.Run("fail on missing config file", func(t *testing.T) {
+		t.Setenv("AOC_CONFIG", filepath.Join(t.TempDir(), "missing.json"))
+		if _, got := config.Load(); got == nil {
+			t.Error("did not fail as expected")
+		}
+	})
+}
+
+func TestWriteTable(t *testing.T) {
+	cfg := config.Default()
+	var b bytes.Buffer
+	if err := cfg.WriteTable(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"config file: none", "input_dir", "./years", "AOC_INPUT_DIR", "AOC_HISTORY_FILE", "AOC_CACHE_DIR"} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("missing %q in %q", want, b.String())
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Package difftest compares solvers against reference implementations on
+// random inputs, shrinking any disagreement to a minimal counterexample.
+package difftest
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// Config for a differential test.
+type Config struct {
+	// Iterations is the number of random inputs to try.
+	Iterations int
+	// Seed for the random inputs, or zero to pick one from the clock.
+	Seed int64
+}
+
+// Check compares candidate against reference on inputs from generate. The
+// first input they disagree on, including by one of them panicking, is
+// shrunk to a minimal counterexample and reported along with the seed.
+// Neither function may modify its input.
+func Check[T any, R comparable](t testing.TB, config Config, generate func(*rand.Rand) T, shrink func(T) []T, reference, candidate func(T) R) {
+	t.Helper()
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	iterations := config.Iterations
+	if testing.Short() && iterations > 100 {
+		iterations = 100
+	}
+	rng := rand.New(rand.NewSource(seed))
+	differs := func(input T) bool {
+		return compare(input, reference, candidate) != ""
+	}
+	for i := 0; i < iterations; i++ {
+		input := generate(rng)
+		if !differs(input) {
+			continue
+		}
+		input = Minimize(input, shrink, differs)
+		t.Fatalf("seed %d: %s on input %v", seed, compare(input, reference, candidate), input)
+		return
+	}
+}
+
+// Minimize input by repeatedly replacing it with the first smaller candidate
+// from shrink that still fails, until none do.
+func Minimize[T any](input T, shrink func(T) []T, fails func(T) bool) T {
+	for {
+		shrunk := false
+		for _, candidate := range shrink(input) {
+			if fails(candidate) {
+				input = candidate
+				shrunk = true
+				break
+			}
+		}
+		if !shrunk {
+			return input
+		}
+	}
+}
+
+// ShrinkSlice returns smaller copies of s, first with each half removed and
+// then with each single element removed.
+func ShrinkSlice[E any](s []E) [][]E {
+	var candidates [][]E
+	if len(s) > 1 {
+		half := len(s) / 2
+		candidates = append(candidates, clone(s[:half]), clone(s[half:]))
+	}
+	for i := range s {
+		candidate := make([]E, 0, len(s)-1)
+		candidate = append(candidate, s[:i]...)
+		candidate = append(candidate, s[i+1:]...)
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+func clone[E any](s []E) []E {
+	return append([]E(nil), s...)
+}
+
+// compare describes how the results of reference and candidate differ for
+// input, or returns "" if they agree.
+func compare[T any, R comparable](input T, reference, candidate func(T) R) string {
+	want, wantPanic := call(reference, input)
+	got, gotPanic := call(candidate, input)
+	switch {
+	case wantPanic != nil:
+		return fmt.Sprintf("reference panicked: %v", wantPanic)
+	case gotPanic != nil:
+		return fmt.Sprintf("candidate panicked: %v", gotPanic)
+	case got != want:
+		return fmt.Sprintf("got: %v, want: %v", got, want)
+	}
+	return ""
+}
+
+func call[T any, R any](f func(T) R, input T) (result R, recovered interface{}) {
+	defer func() {
+		recovered = recover()
+	}()
+	return f(input), nil
+}
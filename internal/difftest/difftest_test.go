@@ -0,0 +1,112 @@
+package difftest_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/difftest"
+)
+
+// recorder captures the failure reported by Check.
+type recorder struct {
+	testing.TB
+	failure string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.failure = fmt.Sprintf(format, args...)
+}
+
+func randomInts(rng *rand.Rand) []int {
+	ints := make([]int, rng.Intn(50))
+	for i := range ints {
+		ints[i] = rng.Intn(100)
+	}
+	return ints
+}
+
+func sum(ints []int) int {
+	total := 0
+	for _, n := range ints {
+		total += n
+	}
+	return total
+}
+
+func TestCheck(t *testing.T) {
+	config := difftest.Config{Iterations: 1000, Seed: 1}
+	t.Run("pass when implementations agree", func(t *testing.T) {
+		r := &recorder{TB: t}
+		difftest.Check(r, config, randomInts, difftest.ShrinkSlice[int], sum, sum)
+		if r.failure != "" {
+			t.Errorf("unexpected failure: %s", r.failure)
+		}
+	})
+	t.Run("shrink a mismatch to a minimal counterexample", func(t *testing.T) {
+		ignoresLarge := func(ints []int) int {
+			total := 0
+			for _, n := range ints {
+				if n < 90 {
+					total += n
+				}
+			}
+			return total
+		}
+		r := &recorder{TB: t}
+		difftest.Check(r, config, randomInts, difftest.ShrinkSlice[int], sum, ignoresLarge)
+		if !strings.HasPrefix(r.failure, "seed 1: got: ") {
+			t.Fatalf("got: %q", r.failure)
+		}
+		if !strings.Contains(r.failure, "on input [9") {
+			t.Errorf("counterexample not minimal: %q", r.failure)
+		}
+	})
+	t.Run("report a panicking candidate", func(t *testing.T) {
+		panics := func(ints []int) int {
+			return ints[0]
+		}
+		first := func(ints []int) int {
+			if len(ints) == 0 {
+				return 0
+			}
+			return ints[0]
+		}
+		r := &recorder{TB: t}
+		difftest.Check(r, config, randomInts, difftest.ShrinkSlice[int], first, panics)
+		if !strings.Contains(r.failure, "candidate panicked") || !strings.HasSuffix(r.failure, "on input []") {
+			t.Errorf("got: %q", r.failure)
+		}
+	})
+}
+
+func TestMinimize(t *testing.T) {
+	containsSeven := func(ints []int) bool {
+		for _, n := range ints {
+			if n == 7 {
+				return true
+			}
+		}
+		return false
+	}
+	got := difftest.Minimize([]int{1, 2, 7, 3, 7, 4}, difftest.ShrinkSlice[int], containsSeven)
+	want := []int{7}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
+
+func TestShrinkSlice(t *testing.T) {
+	got := difftest.ShrinkSlice([]int{1, 2, 3})
+	want := [][]int{{1}, {2, 3}, {2, 3}, {1, 3}, {1, 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+	if got := difftest.ShrinkSlice([]int{}); len(got) != 0 {
+		t.Errorf("got: %v, want no candidates", got)
+	}
+}
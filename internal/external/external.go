@@ -0,0 +1,135 @@
+// Package external runs puzzle solvers written in other languages so their
+// answers and timing can be compared with the Go solvers.
+//
+// An external solver is an executable that follows the same contract as the
+// command for each Go day. It is run with the arguments
+//
+//	-input - -json
+//
+// followed by -part-2 when solving part 2, and with AOC_EXTERNAL_YEAR,
+// AOC_EXTERNAL_DAY and AOC_EXTERNAL_PART set in its environment for
+// executables that solve more than one day. These are not settings of the
+// config package, so a solver that loads the config still sees the user's own
+// AOC_YEAR. The puzzle input is written to its stdin, and it writes a JSON
+// object with an "answer" field to its stdout, optionally with an "error"
+// field instead. A non-zero exit status is a failure, described by anything
+// it wrote to stderr. The Go days can be driven the same way, for example with
+//
+//	go run ./cmd/2021/day_01 -input - -json -part-2
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+)
+
+// Environment variables naming the puzzle an external solver is run for.
+const (
+	YearEnv = "AOC_EXTERNAL_YEAR"
+	DayEnv  = "AOC_EXTERNAL_DAY"
+	PartEnv = "AOC_EXTERNAL_PART"
+)
+
+// Command that runs an external solver, with the executable first.
+type Command []string
+
+// SolveFunc adapts the external solver to solve one part of a day of a year.
+func (c Command) SolveFunc(year, day, part int) aoc.SolveFunc {
+	return func(ctx context.Context, input io.Reader) (int, error) {
+		if len(c) == 0 {
+			return 0, errors.New("must provide a command")
+		}
+		args := append(c[1:len(c):len(c)], "-input", "-", "-json")
+		if part == 2 {
+			args = append(args, "-part-2")
+		}
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, c[0], args...)
+		cmd.Env = append(os.Environ(),
+			YearEnv+"="+strconv.Itoa(year),
+			DayEnv+"="+strconv.Itoa(day),
+			PartEnv+"="+strconv.Itoa(part),
+		)
+		cmd.Stdin = input
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return 0, ctxErr
+			}
+			if message := strings.TrimSpace(stderr.String()); message != "" {
+				return 0, fmt.Errorf("%w: %s", err, message)
+			}
+			return 0, err
+		}
+		var answer aoc.Answer
+		if err := json.Unmarshal(stdout.Bytes(), &answer); err != nil {
+			return 0, fmt.Errorf("invalid answer %q: %w", stdout.String(), err)
+		}
+		if answer.Error != "" {
+			return 0, errors.New(answer.Error)
+		}
+		return answer.Answer, nil
+	}
+}
+
+// Outcome of solving one part with one implementation.
+type Outcome struct {
+	Answer   int
+	Err      error
+	Duration time.Duration
+}
+
+// Comparison of the Go and external solvers on one part of a puzzle input.
+type Comparison struct {
+	Part     int
+	Go       Outcome
+	External Outcome
+}
+
+// Match reports whether both solvers succeeded with the same answer.
+func (c Comparison) Match() bool {
+	return c.Go.Err == nil && c.External.Err == nil && c.Go.Answer == c.External.Answer
+}
+
+// Compare the Go solver with the external command on both parts of input,
+// stopping each of the four solves once timeout has passed when it is greater
+// than zero.
+func Compare(ctx context.Context, solver aoc.Solver, command Command, input []byte, timeout time.Duration) []Comparison {
+	comparisons := make([]Comparison, 2)
+	for i := range comparisons {
+		part := i + 1
+		comparisons[i] = Comparison{
+			Part:     part,
+			Go:       solve(ctx, solver.Part(part), input, timeout),
+			External: solve(ctx, command.SolveFunc(solver.Year, solver.Day, part), input, timeout),
+		}
+	}
+	return comparisons
+}
+
+func solve(ctx context.Context, solve aoc.SolveFunc, input []byte, timeout time.Duration) Outcome {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	start := time.Now()
+	answer, err := aoc.Solve(ctx, solve, bytes.NewReader(input))
+	return Outcome{
+		Answer:   answer,
+		Err:      err,
+		Duration: time.Since(start),
+	}
+}
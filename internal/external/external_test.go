@@ -0,0 +1,159 @@
+package external_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/external"
+	"github.com/dugword/advent-of-code-2021/years/2021/day01"
+)
+
+// helperModeEnv selects how the test binary behaves when run as an external
+// solver.
+const helperModeEnv = "AOC_EXTERNAL_HELPER"
+
+func TestMain(m *testing.M) {
+	switch os.Getenv(helperModeEnv) {
+	case "":
+		os.Exit(m.Run())
+	case "go":
+		if err := day01.Run(os.Args, os.Stdout, os.Stderr); err != nil {
+			fmt.Fprint(os.Stderr, err)
+			os.Exit(1)
+		}
+	case "wrong":
+		fmt.Printf(`{"answer": 42, "day": %s}`, os.Getenv(external.DayEnv))
+	case "env":
+		if year := os.Getenv("AOC_YEAR"); year != "2015" {
+			fmt.Printf(`{"error": "AOC_YEAR is %q"}`, year)
+			break
+		}
+		fmt.Printf(`{"answer": %s%s%s}`, os.Getenv(external.YearEnv), os.Getenv(external.DayEnv), os.Getenv(external.PartEnv))
+	case "error":
+		fmt.Print(`{"error": "unsupported"}`)
+	case "garbage":
+		fmt.Print("42")
+	case "fail":
+		fmt.Fprint(os.Stderr, "crashed")
+		os.Exit(2)
+	case "slow":
+		time.Sleep(10 * time.Second)
+	case "nap":
+		time.Sleep(400 * time.Millisecond)
+		fmt.Print(`{"answer": 42}`)
+	}
+	os.Exit(0)
+}
+
+func helper(t *testing.T, mode string) external.Command {
+	t.Helper()
+	t.Setenv(helperModeEnv, mode)
+	return external.Command{os.Args[0]}
+}
+
+func TestSolveFunc(t *testing.T) {
+	input := "199\n200\n208\n210\n200\n207\n240\n269\n260\n263\n"
+	t.Run("drive a Go day through the protocol", func(t *testing.T) {
+		command := helper(t, "go")
+		for part, want := range map[int]int{1: 7, 2: 5} {
+			got, err := command.SolveFunc(2021, 1, part)(context.Background(), strings.NewReader(input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("part %d got: %d, want: %d", part, got, want)
+			}
+		}
+	})
+	t.Run("name the puzzle without changing the config", func(t *testing.T) {
+		t.Setenv("AOC_YEAR", "2015")
+		got, err := helper(t, "env").SolveFunc(2021, 3, 2)(context.Background(), strings.NewReader(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := 202132; got != want {
+			t.Errorf("got: %d, want: %d", got, want)
+		}
+	})
+	testCases := []struct {
+		mode string
+		want string
+	}{
+		{mode: "error", want: "unsupported"},
+		{mode: "garbage", want: `invalid answer "42": json: cannot unmarshal number into Go value of type aoc.Answer`},
+		{mode: "fail", want: "exit status 2: crashed"},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run("fail on "+testCase.mode, func(t *testing.T) {
+			command := helper(t, testCase.mode)
+			_, got := command.SolveFunc(2021, 1, 1)(context.Background(), strings.NewReader(input))
+			if got == nil {
+				t.Fatal("did not fail as expected")
+			}
+			if got.Error() != testCase.want {
+				t.Errorf("got: %q, want: %q", got, testCase.want)
+			}
+		})
+	}
+	t.Run("fail on timeout", func(t *testing.T) {
+		command := helper(t, "slow")
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, got := aoc.Solve(ctx, command.SolveFunc(2021, 1, 1), strings.NewReader(input))
+		if got != aoc.ErrTimeout {
+			t.Errorf("got: %v, want: %v", got, aoc.ErrTimeout)
+		}
+	})
+	t.Run("fail on missing command", func(t *testing.T) {
+		_, got := external.Command{}.SolveFunc(2021, 1, 1)(context.Background(), strings.NewReader(input))
+		if got == nil {
+			t.Error("did not fail as expected")
+		}
+	})
+}
+
+func TestCompare(t *testing.T) {
+	solver, ok := aoc.Lookup(2021, 1)
+	if !ok {
+		t.Fatal("day 1 is not registered")
+	}
+	input := []byte("199\n200\n208\n210\n200\n207\n240\n269\n260\n263\n")
+	t.Run("match the Go solver", func(t *testing.T) {
+		for _, comparison := range external.Compare(context.Background(), solver, helper(t, "go"), input, 0) {
+			if !comparison.Match() {
+				t.Errorf("part %d: go %+v, external %+v", comparison.Part, comparison.Go, comparison.External)
+			}
+		}
+	})
+	t.Run("time out each solve on its own", func(t *testing.T) {
+		// Both naps together take longer than the timeout, but each takes less.
+		for _, comparison := range external.Compare(context.Background(), solver, helper(t, "nap"), input, 700*time.Millisecond) {
+			if comparison.External.Err != nil {
+				t.Errorf("part %d got: %v, want: <nil>", comparison.Part, comparison.External.Err)
+			}
+		}
+	})
+	t.Run("time out a slow solve", func(t *testing.T) {
+		for _, comparison := range external.Compare(context.Background(), solver, helper(t, "slow"), input, 50*time.Millisecond) {
+			if comparison.External.Err != aoc.ErrTimeout {
+				t.Errorf("part %d got: %v, want: %v", comparison.Part, comparison.External.Err, aoc.ErrTimeout)
+			}
+		}
+	})
+	t.Run("detect a mismatch", func(t *testing.T) {
+		for _, comparison := range external.Compare(context.Background(), solver, helper(t, "wrong"), input, 0) {
+			if comparison.Match() {
+				t.Errorf("part %d matched unexpectedly", comparison.Part)
+			}
+			if comparison.External.Answer != 42 {
+				t.Errorf("got: %d, want: 42", comparison.External.Answer)
+			}
+		}
+	})
+}
@@ -0,0 +1,158 @@
+// Package history records every part solved by the aoc run command in a JSON
+// lines file, and reports how the answer and performance of each part have
+// changed over time.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+)
+
+// Record of solving one part in one run, written as a line of the history
+// file.
+type Record struct {
+	Time   time.Time `json:"time"`
+	Commit string    `json:"commit,omitempty"`
+	Year   int       `json:"year"`
+	Day    int       `json:"day"`
+	Part   int       `json:"part"`
+	Answer int       `json:"answer"`
+	Error  string    `json:"error,omitempty"`
+	// InputHash is the hex encoded SHA-256 of the input.
+	InputHash string        `json:"input_hash,omitempty"`
+	Duration  time.Duration `json:"duration_ns"`
+	// Allocs is zero when the run did not measure them.
+	Allocs uint64 `json:"allocs,omitempty"`
+}
+
+// Result is the answer of the record, or the error it failed with.
+func (r Record) Result() string {
+	if r.Error != "" {
+		return "error: " + r.Error
+	}
+	return fmt.Sprint(r.Answer)
+}
+
+// Records of the results of a report, run at time t from commit. Cached
+// results were not solved by the run, and results that timed out or were
+// cancelled say nothing about the answer or the build, so they are left out.
+func Records(report aoc.Report, t time.Time, commit string) []Record {
+	records := make([]Record, 0, len(report.Results))
+	for _, result := range report.Results {
+		if result.Cached || aoc.IsStopped(result.Err) {
+			continue
+		}
+		record := Record{
+			Time:      t,
+			Commit:    commit,
+			Year:      result.Year,
+			Day:       result.Day,
+			Part:      result.Part,
+			Answer:    result.Answer,
+			InputHash: result.InputHash,
+			Duration:  result.Wall,
+			Allocs:    result.Allocs,
+		}
+		if result.Err != nil {
+			record.Error = result.Err.Error()
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// Append records to the history file at path, creating it and its directory
+// if they do not exist.
+func Append(path string, records []Record) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	return file.Close()
+}
+
+// Load the records of the history file at path, or none if it does not
+// exist.
+func Load(path string) ([]Record, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Commit returns the git commit the running binary was built from, or else
+// the commit checked out in the working directory, suffixed with "-dirty" if
+// there were uncommitted changes. It is empty if neither is known.
+func Commit() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		revision, modified := "", false
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				revision = setting.Value
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			}
+		}
+		if revision != "" {
+			return shortCommit(revision, modified)
+		}
+	}
+	revision, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	status, err := exec.Command("git", "status", "--porcelain", "--untracked-files=no").Output()
+	return shortCommit(strings.TrimSpace(string(revision)), err != nil || len(status) > 0)
+}
+
+func shortCommit(revision string, modified bool) string {
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if modified {
+		revision += "-dirty"
+	}
+	return revision
+}
@@ -0,0 +1,152 @@
+package history_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/history"
+)
+
+var start = time.Date(2021, 12, 1, 5, 0, 0, 0, time.UTC)
+
+func TestRecords(t *testing.T) {
+	report := aoc.Report{Results: []aoc.Result{
+		{Year: 2021, Day: 3, Part: 1, Answer: 198, Wall: time.Millisecond, InputHash: "abc", Allocs: 7},
+		{Year: 2021, Day: 3, Part: 2, Err: errors.New("broken")},
+		{Year: 2021, Day: 4, Part: 1, Answer: 5, Cached: true},
+		{Year: 2021, Day: 4, Part: 2, Err: aoc.ErrTimeout, Wall: time.Second},
+		{Year: 2021, Day: 5, Part: 1, Err: fmt.Errorf("day 5: %w", context.Canceled)},
+	}}
+	want := []history.Record{
+		{Time: start, Commit: "c1", Year: 2021, Day: 3, Part: 1, Answer: 198, InputHash: "abc", Duration: time.Millisecond, Allocs: 7},
+		{Time: start, Commit: "c1", Year: 2021, Day: 3, Part: 2, Error: "broken"},
+	}
+	if got := history.Records(report, start, "c1"); !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %+v, want: %+v", got, want)
+	}
+}
+
+func TestAppendLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".aoc", "history.jsonl")
+	t.Run("load nothing from a missing file", func(t *testing.T) {
+		got, err := history.Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 0 {
+			t.Errorf("got: %+v, want none", got)
+		}
+	})
+	t.Run("load the appended records", func(t *testing.T) {
+		first := []history.Record{{Time: start, Year: 2021, Day: 1, Part: 1, Answer: 7}}
+		second := []history.Record{{Time: start.Add(time.Hour), Commit: "c2", Year: 2021, Day: 1, Part: 1, Error: "broken"}}
+		for _, records := range [][]history.Record{first, second} {
+			if err := history.Append(path, records); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got, err := history.Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := append(first, second...); !reflect.DeepEqual(got, want) {
+			t.Errorf("got: %+v, want: %+v", got, want)
+		}
+	})
+	t.Run("fail on invalid line", func(t *testing.T) {
+		invalid := filepath.Join(t.TempDir(), "history.jsonl")
+		if err := os.WriteFile(invalid, []byte("{}\nblue\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, got := history.Load(invalid)
+		if got == nil || !strings.HasPrefix(got.Error(), invalid+":2:") {
+			t.Errorf("got: %v, want error on line 2", got)
+		}
+	})
+}
+
+func TestTrends(t *testing.T) {
+	run := func(hours int, commit string, part, answer int, input string, duration time.Duration) history.Record {
+		return history.Record{
+			Time:      start.Add(time.Duration(hours) * time.Hour),
+			Commit:    commit,
+			Year:      2021,
+			Day:       3,
+			Part:      part,
+			Answer:    answer,
+			InputHash: input,
+			Duration:  duration,
+		}
+	}
+	records := []history.Record{
+		run(3, "c2", 1, 199, "a", 30*time.Millisecond),
+		run(0, "c1", 1, 198, "a", 10*time.Millisecond),
+		run(1, "c1", 1, 198, "a", 20*time.Millisecond),
+		run(2, "c1", 1, 198, "a", 12*time.Millisecond),
+		run(4, "c2", 1, 5, "b", 30*time.Millisecond),
+		run(0, "c1", 2, 230, "a", time.Millisecond),
+	}
+	// Only some runs measured their allocations.
+	records[2].Allocs = 40
+	records[3].Allocs = 60
+	trends := history.Trends(records)
+	if len(trends) != 2 || trends[0].Part != 1 || trends[1].Part != 2 {
+		t.Fatalf("got: %+v, want parts 1 and 2", trends)
+	}
+	t.Run("summarize each build", func(t *testing.T) {
+		want := []history.Build{
+			{Commit: "c1", First: start, Runs: 3, Result: "198", Duration: 12 * time.Millisecond, Allocs: 40},
+			{Commit: "c2", First: start.Add(3 * time.Hour), Runs: 2, Result: "5", Duration: 30 * time.Millisecond},
+		}
+		if got := trends[0].Builds; !reflect.DeepEqual(got, want) {
+			t.Errorf("got: %+v, want: %+v", got, want)
+		}
+	})
+	t.Run("report answer changes of the same input", func(t *testing.T) {
+		want := []history.Change{{Time: start.Add(3 * time.Hour), Commit: "c2", From: "198", To: "199"}}
+		if got := trends[0].Changes; !reflect.DeepEqual(got, want) {
+			t.Errorf("got: %+v, want: %+v", got, want)
+		}
+	})
+	t.Run("compare the latest build with the previous", func(t *testing.T) {
+		if got, ok := trends[0].Slowdown(); !ok || got != 1.5 {
+			t.Errorf("got: %v %v, want: %v", got, ok, 1.5)
+		}
+		if _, ok := trends[1].Slowdown(); ok {
+			t.Error("got slowdown of a single build")
+		}
+	})
+	t.Run("write the trends", func(t *testing.T) {
+		var w bytes.Buffer
+		if err := history.WriteTrends(&w, trends); err != nil {
+			t.Fatal(err)
+		}
+		want := "YEAR  DAY  PART  RUNS  BUILDS  ANSWER  MEDIAN  ALLOCS  TREND    CHANGES\n" +
+			"2021  3    1     5     2       5       30ms    -       +150.0%  1\n" +
+			"2021  3    2     1     1       230     1ms     -       -        0\n" +
+			"2021 day 3 part 1 changed from 198 to 199 at 2021-12-01T08:00:00Z in c2\n"
+		if got := w.String(); got != want {
+			t.Errorf("got: %q, want: %q", got, want)
+		}
+	})
+	t.Run("write the builds", func(t *testing.T) {
+		var w bytes.Buffer
+		if err := history.WriteBuilds(&w, trends[1:]); err != nil {
+			t.Fatal(err)
+		}
+		want := "YEAR  DAY  PART  COMMIT  FIRST RUN             RUNS  ANSWER  MEDIAN  ALLOCS\n" +
+			"2021  3    2     c1      2021-12-01T05:00:00Z  1     230     1ms     -\n"
+		if got := w.String(); got != want {
+			t.Errorf("got: %q, want: %q", got, want)
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package history
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Build summarizes the runs of a part at one commit.
+type Build struct {
+	Commit string
+	// First is when the part was first run at the commit.
+	First time.Time
+	Runs  int
+	// Result of the latest run at the commit.
+	Result string
+	// Duration and Allocs are the medians of the runs at the commit. Allocs
+	// only counts the runs that measured them, and is zero if none did.
+	Duration time.Duration
+	Allocs   uint64
+}
+
+// Change of the result of a part between two consecutive runs of the same
+// input.
+type Change struct {
+	Time   time.Time
+	Commit string
+	From   string
+	To     string
+}
+
+// Trend of one part over time.
+type Trend struct {
+	Year int
+	Day  int
+	Part int
+	// Builds in the order they were first run.
+	Builds  []Build
+	Changes []Change
+}
+
+// Slowdown is the relative change in median duration from the previous build
+// to the latest, such as 0.1 for 10% slower, and false if there is only one
+// build.
+func (t Trend) Slowdown() (float64, bool) {
+	if len(t.Builds) < 2 {
+		return 0, false
+	}
+	previous, latest := t.Builds[len(t.Builds)-2], t.Builds[len(t.Builds)-1]
+	if previous.Duration == 0 {
+		return 0, false
+	}
+	return float64(latest.Duration)/float64(previous.Duration) - 1, true
+}
+
+type partKey struct {
+	year, day, part int
+}
+
+// Trends of each part in the records, ordered by year, day and part.
+func Trends(records []Record) []Trend {
+	records = slices.Clone(records)
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+	byPart := make(map[partKey][]Record)
+	for _, record := range records {
+		key := partKey{record.Year, record.Day, record.Part}
+		byPart[key] = append(byPart[key], record)
+	}
+	trends := make([]Trend, 0, len(byPart))
+	for key, runs := range byPart {
+		trends = append(trends, trend(key, runs))
+	}
+	sort.Slice(trends, func(i, j int) bool {
+		a, b := trends[i], trends[j]
+		if a.Year != b.Year {
+			return a.Year < b.Year
+		}
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		return a.Part < b.Part
+	})
+	return trends
+}
+
+// trend of a part from its runs in the order they were run.
+func trend(key partKey, runs []Record) Trend {
+	t := Trend{Year: key.year, Day: key.day, Part: key.part}
+	byCommit := make(map[string][]Record)
+	var commits []string
+	for i, run := range runs {
+		if _, ok := byCommit[run.Commit]; !ok {
+			commits = append(commits, run.Commit)
+		}
+		byCommit[run.Commit] = append(byCommit[run.Commit], run)
+		if i == 0 {
+			continue
+		}
+		previous := runs[i-1]
+		if previous.InputHash == run.InputHash && previous.Result() != run.Result() {
+			t.Changes = append(t.Changes, Change{
+				Time:   run.Time,
+				Commit: run.Commit,
+				From:   previous.Result(),
+				To:     run.Result(),
+			})
+		}
+	}
+	for _, commit := range commits {
+		runs := byCommit[commit]
+		durations := make([]time.Duration, len(runs))
+		var allocs []uint64
+		for i, run := range runs {
+			durations[i] = run.Duration
+			if run.Allocs > 0 {
+				allocs = append(allocs, run.Allocs)
+			}
+		}
+		t.Builds = append(t.Builds, Build{
+			Commit:   commit,
+			First:    runs[0].Time,
+			Runs:     len(runs),
+			Result:   runs[len(runs)-1].Result(),
+			Duration: median(durations),
+			Allocs:   median(allocs),
+		})
+	}
+	return t
+}
+
+// median of values, the lower of the middle two for an even count, or zero if
+// there are none.
+func median[T cmp.Ordered](values []T) T {
+	if len(values) == 0 {
+		var zero T
+		return zero
+	}
+	values = slices.Clone(values)
+	slices.Sort(values)
+	return values[(len(values)-1)/2]
+}
+
+// WriteTrends writes a table of the latest build of each part, how its median
+// duration changed from the build before, and how often its answer changed,
+// followed by every change of answer.
+func WriteTrends(w io.Writer, trends []Trend) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "YEAR\tDAY\tPART\tRUNS\tBUILDS\tANSWER\tMEDIAN\tALLOCS\tTREND\tCHANGES")
+	for _, t := range trends {
+		runs := 0
+		for _, build := range t.Builds {
+			runs += build.Runs
+		}
+		latest := t.Builds[len(t.Builds)-1]
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%d\t%s\t%s\t%s\t%s\t%d\n", t.Year, t.Day, t.Part, runs, len(t.Builds), latest.Result, latest.Duration.Round(time.Microsecond), formatAllocs(latest.Allocs), formatSlowdown(t), len(t.Changes))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	for _, t := range trends {
+		for _, change := range t.Changes {
+			_, err := fmt.Fprintf(w, "%d day %d part %d changed from %s to %s at %s in %s\n", t.Year, t.Day, t.Part, change.From, change.To, change.Time.Format(time.RFC3339), formatCommit(change.Commit))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteBuilds writes a table of every build of each part.
+func WriteBuilds(w io.Writer, trends []Trend) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "YEAR\tDAY\tPART\tCOMMIT\tFIRST RUN\tRUNS\tANSWER\tMEDIAN\tALLOCS")
+	for _, t := range trends {
+		for _, build := range t.Builds {
+			fmt.Fprintf(tw, "%d\t%d\t%d\t%s\t%s\t%d\t%s\t%s\t%s\n", t.Year, t.Day, t.Part, formatCommit(build.Commit), build.First.Format(time.RFC3339), build.Runs, build.Result, build.Duration.Round(time.Microsecond), formatAllocs(build.Allocs))
+		}
+	}
+	return tw.Flush()
+}
+
+func formatSlowdown(t Trend) string {
+	slowdown, ok := t.Slowdown()
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%+.1f%%", 100*slowdown)
+}
+
+// formatAllocs as "-" when they were not measured.
+func formatAllocs(allocs uint64) string {
+	if allocs == 0 {
+		return "-"
+	}
+	return fmt.Sprint(allocs)
+}
+
+func formatCommit(commit string) string {
+	if commit == "" {
+		return "unknown"
+	}
+	return commit
+}
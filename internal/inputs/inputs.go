@@ -0,0 +1,241 @@
+// Package inputs reads puzzle inputs. Lines splits an input into its
+// non-blank lines, and the loaders Ints, Binary, Pairs, Grid and Blocks parse
+// the common shapes of input, reporting errors by line number.
+//
+// It also encrypts puzzle inputs so they can be committed without publishing
+// them, and opens them transparently when a key is present.
+//
+// An encrypted input is stored beside where the plain input would be, with
+// EncryptedExt appended to its name. It is sealed with AES-GCM under the key
+// in the KeyEnv environment variable, which is hex encoded and 16, 24 or 32
+// bytes long.
+package inputs
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/dugword/advent-of-code-2021/internal/logging"
+)
+
+const (
+	// KeyEnv is the environment variable holding the key.
+	KeyEnv = "AOC_INPUT_KEY"
+	// EncryptedExt is appended to the name of an encrypted input.
+	EncryptedExt = ".enc"
+)
+
+// magic starts every encrypted input, and is authenticated with it.
+var magic = []byte("aoc-input-v1\n")
+
+var (
+	// ErrNoKey is returned for an encrypted input when no key is set.
+	ErrNoKey = errors.New(KeyEnv + " is not set")
+	// ErrNotEncrypted is returned when decrypting a file that was not
+	// encrypted by Encrypt.
+	ErrNotEncrypted = errors.New("not an encrypted input")
+	// ErrDecrypt is returned when an encrypted input does not authenticate,
+	// because the key is wrong or the input has been tampered with.
+	ErrDecrypt = errors.New("wrong key or tampered input")
+)
+
+// ParseKey decodes a hex encoded key.
+func ParseKey(s string) ([]byte, error) {
+	key, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", KeyEnv, err)
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("invalid %s: must be 16, 24 or 32 bytes, not %d", KeyEnv, len(key))
+	}
+}
+
+// KeyFromEnv returns the key in the environment, or ErrNoKey if it is not set.
+func KeyFromEnv() ([]byte, error) {
+	s := os.Getenv(KeyEnv)
+	if s == "" {
+		return nil, ErrNoKey
+	}
+	return ParseKey(s)
+}
+
+// NewKey returns a random hex encoded key of 32 bytes.
+func NewKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// Encrypt an input under key.
+func Encrypt(key, input []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	encrypted := append(append([]byte{}, magic...), nonce...)
+	return gcm.Seal(encrypted, nonce, input, magic), nil
+}
+
+// Decrypt an input encrypted under key.
+func Decrypt(key, encrypted []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(encrypted, magic) {
+		return nil, ErrNotEncrypted
+	}
+	sealed := encrypted[len(magic):]
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrDecrypt
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	input, err := gcm.Open(nil, nonce, ciphertext, magic)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return input, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptFile encrypts the input at path to path+EncryptedExt, unless that
+// already decrypts to the same input. Each encryption uses a fresh nonce, so
+// rewriting it would change the file without changing the input.
+func EncryptFile(key []byte, path string) error {
+	input, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if encrypted, err := os.ReadFile(path + EncryptedExt); err == nil {
+		if previous, err := Decrypt(key, encrypted); err == nil && bytes.Equal(previous, input) {
+			return nil
+		}
+	}
+	encrypted, err := Encrypt(key, input)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path+EncryptedExt, encrypted, 0o644)
+}
+
+// DecryptFile decrypts the input at path+EncryptedExt to path.
+func DecryptFile(key []byte, path string) error {
+	encrypted, err := os.ReadFile(path + EncryptedExt)
+	if err != nil {
+		return err
+	}
+	input, err := Decrypt(key, encrypted)
+	if err != nil {
+		return fmt.Errorf("%s%s: %w", path, EncryptedExt, err)
+	}
+	return os.WriteFile(path, input, 0o600)
+}
+
+// ReadFile reads the input at path, or if it does not exist decrypts
+// path+EncryptedExt with the key from the environment.
+func ReadFile(path string) ([]byte, error) {
+	input, err := os.ReadFile(path)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return input, err
+	}
+	encrypted, encErr := os.ReadFile(path + EncryptedExt)
+	if errors.Is(encErr, fs.ErrNotExist) {
+		return nil, err
+	}
+	if encErr != nil {
+		return nil, encErr
+	}
+	key, keyErr := KeyFromEnv()
+	if keyErr != nil {
+		return nil, fmt.Errorf("%s%s: %w", path, EncryptedExt, keyErr)
+	}
+	input, err = Decrypt(key, encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("%s%s: %w", path, EncryptedExt, err)
+	}
+	return input, nil
+}
+
+// Open the input at path like ReadFile.
+func Open(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err == nil {
+		return file, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	input, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(input)), nil
+}
+
+// Lines reads the lines of an input, skipping blank lines and logging how many
+// were read and skipped.
+func Lines(ctx context.Context, r io.Reader) ([]string, error) {
+	numbered, err := readLines(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range numbered {
+		lines = append(lines, line.text)
+	}
+	return lines, nil
+}
+
+// line of an input with its number, counting from 1.
+type line struct {
+	number int
+	text   string
+}
+
+// readLines reads the non-blank lines of an input like Lines, keeping their
+// numbers for errors.
+func readLines(ctx context.Context, r io.Reader) ([]line, error) {
+	contents, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var lines []line
+	skipped := 0
+	if len(contents) > 0 {
+		for i, text := range strings.Split(strings.TrimSuffix(string(contents), "\n"), "\n") {
+			if text == "" {
+				skipped++
+				continue
+			}
+			lines = append(lines, line{number: i + 1, text: text})
+		}
+	}
+	logging.FromContext(ctx).DebugContext(ctx, "read input", "bytes", len(contents), "lines", len(lines), "skipped", skipped)
+	return lines, nil
+}
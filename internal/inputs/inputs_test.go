@@ -0,0 +1,340 @@
+package inputs_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/inputs"
+	"github.com/dugword/advent-of-code-2021/internal/logging"
+)
+
+func newKey(t *testing.T) []byte {
+	t.Helper()
+	s, err := inputs.NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := inputs.ParseKey(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestParseKey(t *testing.T) {
+	testCases := []struct {
+		name  string
+		key   string
+		valid bool
+	}{
+		{name: "16 bytes", key: "000102030405060708090a0b0c0d0e0f", valid: true},
+		{name: "32 bytes", key: "000102030405060708090a0b0c0d0e0f000102030405060708090a0b0c0d0e0f", valid: true},
+		{name: "wrong length", key: "0001020304", valid: false},
+		{name: "not hex", key: "secret", valid: false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := inputs.ParseKey(testCase.key)
+			if got := err == nil; got != testCase.valid {
+				t.Errorf("got valid: %t, want: %t, err: %v", got, testCase.valid, err)
+			}
+		})
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	key := newKey(t)
+	input := []byte("199\n200\n208\n")
+	encrypted, err := inputs.Encrypt(key, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Run("decrypt without failure", func(t *testing.T) {
+		got, err := inputs.Decrypt(key, encrypted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, input) {
+			t.Errorf("got: %q, want: %q", got, input)
+		}
+	})
+	t.Run("hide the input", func(t *testing.T) {
+		if bytes.Contains(encrypted, input) {
+			t.Errorf("encrypted input contains the input: %q", encrypted)
+		}
+	})
+	t.Run("fail on wrong key", func(t *testing.T) {
+		_, got := inputs.Decrypt(newKey(t), encrypted)
+		if !errors.Is(got, inputs.ErrDecrypt) {
+			t.Errorf("got: %v, want: %v", got, inputs.ErrDecrypt)
+		}
+	})
+	t.Run("fail on tampered input", func(t *testing.T) {
+		for i := len(encrypted) - 1; i >= len(encrypted)-len(input)-16; i-- {
+			tampered := append([]byte{}, encrypted...)
+			tampered[i] ^= 1
+			if _, got := inputs.Decrypt(key, tampered); !errors.Is(got, inputs.ErrDecrypt) {
+				t.Fatalf("byte %d got: %v, want: %v", i, got, inputs.ErrDecrypt)
+			}
+		}
+	})
+	t.Run("fail on truncated input", func(t *testing.T) {
+		for _, n := range []int{len(encrypted) - 1, 20} {
+			if _, got := inputs.Decrypt(key, encrypted[:n]); !errors.Is(got, inputs.ErrDecrypt) {
+				t.Errorf("length %d got: %v, want: %v", n, got, inputs.ErrDecrypt)
+			}
+		}
+	})
+	t.Run("fail on plain input", func(t *testing.T) {
+		if _, got := inputs.Decrypt(key, input); !errors.Is(got, inputs.ErrNotEncrypted) {
+			t.Errorf("got: %v, want: %v", got, inputs.ErrNotEncrypted)
+		}
+	})
+}
+
+func TestReadFile(t *testing.T) {
+	s, err := inputs.NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := inputs.ParseKey(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, "input")
+	input := []byte("forward 5\n")
+	if err := os.WriteFile(path, input, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := inputs.EncryptFile(key, path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	t.Run("decrypt with the key from the environment", func(t *testing.T) {
+		t.Setenv(inputs.KeyEnv, s)
+		got, err := inputs.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, input) {
+			t.Errorf("got: %q, want: %q", got, input)
+		}
+		file, err := inputs.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		if got, _ := io.ReadAll(file); !bytes.Equal(got, input) {
+			t.Errorf("got: %q, want: %q", got, input)
+		}
+	})
+	t.Run("prefer the plain input", func(t *testing.T) {
+		plainPath := filepath.Join(dir, "plain")
+		if err := os.WriteFile(plainPath+inputs.EncryptedExt, []byte("garbage"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(plainPath, input, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(inputs.KeyEnv, "")
+		got, err := inputs.ReadFile(plainPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, input) {
+			t.Errorf("got: %q, want: %q", got, input)
+		}
+	})
+	t.Run("fail without a key", func(t *testing.T) {
+		t.Setenv(inputs.KeyEnv, "")
+		if _, got := inputs.ReadFile(path); !errors.Is(got, inputs.ErrNoKey) {
+			t.Errorf("got: %v, want: %v", got, inputs.ErrNoKey)
+		}
+	})
+	t.Run("fail on wrong key", func(t *testing.T) {
+		other, err := inputs.NewKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(inputs.KeyEnv, other)
+		if _, got := inputs.Open(path); !errors.Is(got, inputs.ErrDecrypt) {
+			t.Errorf("got: %v, want: %v", got, inputs.ErrDecrypt)
+		}
+	})
+	t.Run("fail on missing input", func(t *testing.T) {
+		t.Setenv(inputs.KeyEnv, s)
+		if _, got := inputs.Open(filepath.Join(dir, "missing")); !errors.Is(got, fs.ErrNotExist) {
+			t.Errorf("got: %v, want: %v", got, fs.ErrNotExist)
+		}
+	})
+	t.Run("decrypt the file", func(t *testing.T) {
+		if err := inputs.DecryptFile(key, path); err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, input) {
+			t.Errorf("got: %q, want: %q", got, input)
+		}
+	})
+	t.Run("keep an encrypted file of the same input", func(t *testing.T) {
+		want, err := os.ReadFile(path + inputs.EncryptedExt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := inputs.EncryptFile(key, path); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := os.ReadFile(path + inputs.EncryptedExt); !bytes.Equal(got, want) {
+			t.Errorf("got: %q, want: %q", got, want)
+		}
+	})
+	t.Run("rewrite the encrypted file of a changed input", func(t *testing.T) {
+		changed := []byte("forward 6\n")
+		if err := os.WriteFile(path, changed, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := inputs.EncryptFile(key, path); err != nil {
+			t.Fatal(err)
+		}
+		encrypted, err := os.ReadFile(path + inputs.EncryptedExt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := inputs.Decrypt(key, encrypted); err != nil || !bytes.Equal(got, changed) {
+			t.Errorf("got: %q, %v, want: %q", got, err, changed)
+		}
+	})
+}
+
+func TestLines(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  []string
+		log   string
+	}{
+		{name: "empty", input: "", want: nil, log: "lines=0 skipped=0"},
+		{name: "trailing newline", input: "1\n2\n", want: []string{"1", "2"}, log: "lines=2 skipped=0"},
+		{name: "blank lines", input: "1\n\n2\n\n", want: []string{"1", "2"}, log: "lines=2 skipped=2"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var log bytes.Buffer
+			ctx := logging.NewContext(context.Background(), logging.New(&log, slog.LevelDebug, logging.FormatText))
+			got, err := inputs.Lines(ctx, strings.NewReader(testCase.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("got: %q, want: %q", got, testCase.want)
+			}
+			if !strings.Contains(log.String(), testCase.log) {
+				t.Errorf("got: %q, want to contain: %q", log.String(), testCase.log)
+			}
+		})
+	}
+}
+
+func TestLoaders(t *testing.T) {
+	ctx := context.Background()
+	testCases := []struct {
+		name  string
+		load  func(io.Reader) (any, error)
+		input string
+		want  any
+		err   string
+	}{
+		{
+			name:  "ints",
+			load:  func(r io.Reader) (any, error) { return inputs.Ints(ctx, r) },
+			input: "199\n-200\n\n208\n",
+			want:  []int{199, -200, 208},
+		},
+		{
+			name:  "ints with a word",
+			load:  func(r io.Reader) (any, error) { return inputs.Ints(ctx, r) },
+			input: "199\n\nblue\n",
+			err:   "line 3: ",
+		},
+		{
+			name: "binary",
+			load: func(r io.Reader) (any, error) {
+				values, width, err := inputs.Binary(ctx, r)
+				return []int{len(values), values[0], values[1], width}, err
+			},
+			input: "00100\n011110\n",
+			want:  []int{2, 4, 30, 6},
+		},
+		{
+			name: "binary with a digit",
+			load: func(r io.Reader) (any, error) {
+				_, _, err := inputs.Binary(ctx, r)
+				return nil, err
+			},
+			input: "00100\n01120\n",
+			err:   "line 2: ",
+		},
+		{
+			name:  "pairs",
+			load:  func(r io.Reader) (any, error) { return inputs.Pairs(ctx, r) },
+			input: "forward 5\ndown  -3\n",
+			want:  []inputs.Pair{{Word: "forward", Number: 5}, {Word: "down", Number: -3}},
+		},
+		{
+			name:  "pairs without a number",
+			load:  func(r io.Reader) (any, error) { return inputs.Pairs(ctx, r) },
+			input: "forward 5\nup\n",
+			err:   `line 2: want a word and a number, got "up"`,
+		},
+		{
+			name:  "grid",
+			load:  func(r io.Reader) (any, error) { return inputs.Grid(ctx, r) },
+			input: "#.#\n.#.\n",
+			want:  [][]byte{[]byte("#.#"), []byte(".#.")},
+		},
+		{
+			name:  "ragged grid",
+			load:  func(r io.Reader) (any, error) { return inputs.Grid(ctx, r) },
+			input: "#.#\n\n.#\n",
+			err:   "line 3: got 2 columns, want 3",
+		},
+		{
+			name:  "blocks",
+			load:  func(r io.Reader) (any, error) { return inputs.Blocks(ctx, r) },
+			input: "7,4,9\n\n22 13\n8 2\n\n\n3 15\n",
+			want:  [][]string{{"7,4,9"}, {"22 13", "8 2"}, {"3 15"}},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := testCase.load(strings.NewReader(testCase.input))
+			if testCase.err != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), testCase.err) {
+					t.Errorf("got: %v, want: %s...", err, testCase.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("got: %v, want: %v", got, testCase.want)
+			}
+		})
+	}
+}
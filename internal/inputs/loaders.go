@@ -0,0 +1,117 @@
+package inputs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dugword/advent-of-code-2021/internal/logging"
+)
+
+// Pair of a word and a number on one line, such as "forward 5".
+type Pair struct {
+	Word   string
+	Number int
+}
+
+// Ints reads one decimal integer per line.
+func Ints(ctx context.Context, r io.Reader) ([]int, error) {
+	lines, err := readLines(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	ints := make([]int, 0, len(lines))
+	for _, line := range lines {
+		n, err := strconv.Atoi(line.text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.number, err)
+		}
+		ints = append(ints, n)
+	}
+	return ints, nil
+}
+
+// Binary reads one binary number per line, with the width in bits of the
+// longest line.
+func Binary(ctx context.Context, r io.Reader) (values []int, width int, err error) {
+	lines, err := readLines(ctx, r)
+	if err != nil {
+		return nil, 0, err
+	}
+	values = make([]int, 0, len(lines))
+	for _, line := range lines {
+		value, err := strconv.ParseInt(line.text, 2, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", line.number, err)
+		}
+		values = append(values, int(value))
+		if len(line.text) > width {
+			width = len(line.text)
+		}
+	}
+	return values, width, nil
+}
+
+// Pairs reads a word and a decimal integer separated by spaces per line.
+func Pairs(ctx context.Context, r io.Reader) ([]Pair, error) {
+	lines, err := readLines(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	pairs := make([]Pair, 0, len(lines))
+	for _, line := range lines {
+		fields := strings.Fields(line.text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: want a word and a number, got %q", line.number, line.text)
+		}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.number, err)
+		}
+		pairs = append(pairs, Pair{Word: fields[0], Number: n})
+	}
+	return pairs, nil
+}
+
+// Grid reads a rectangle of characters, one row per line.
+func Grid(ctx context.Context, r io.Reader) ([][]byte, error) {
+	lines, err := readLines(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	grid := make([][]byte, 0, len(lines))
+	for _, line := range lines {
+		if len(line.text) != len(lines[0].text) {
+			return nil, fmt.Errorf("line %d: got %d columns, want %d", line.number, len(line.text), len(lines[0].text))
+		}
+		grid = append(grid, []byte(line.text))
+	}
+	return grid, nil
+}
+
+// Blocks reads the blocks of lines separated by blank lines.
+func Blocks(ctx context.Context, r io.Reader) ([][]string, error) {
+	contents, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var blocks [][]string
+	var block []string
+	for _, line := range strings.Split(string(contents), "\n") {
+		if line != "" {
+			block = append(block, line)
+			continue
+		}
+		if len(block) > 0 {
+			blocks = append(blocks, block)
+			block = nil
+		}
+	}
+	if len(block) > 0 {
+		blocks = append(blocks, block)
+	}
+	logging.FromContext(ctx).DebugContext(ctx, "read input", "bytes", len(contents), "blocks", len(blocks))
+	return blocks, nil
+}
@@ -0,0 +1,250 @@
+// Package inspect describes the shape of an unfamiliar puzzle input, so that a
+// new day can pick a loader from the inputs package.
+package inspect
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+)
+
+// Formats of the lines of an input, from most to least specific.
+const (
+	FormatBinary     = "binary"
+	FormatInts       = "ints"
+	FormatWordNumber = "word number"
+	FormatGrid       = "grid"
+	FormatBlocks     = "blocks"
+	FormatLines      = "lines"
+)
+
+// maxBinaryWidth is the most binary digits of a line that inputs.Binary can
+// read.
+const maxBinaryWidth = 63
+
+// lineFormats are the formats a single line can match, in the order they
+// are preferred.
+var lineFormats = []string{FormatBinary, FormatInts, FormatWordNumber, FormatGrid}
+
+// loaders suggested for each format.
+var loaders = map[string]string{
+	FormatBinary:     "inputs.Binary(ctx, r)",
+	FormatInts:       "inputs.Ints(ctx, r)",
+	FormatWordNumber: "inputs.Pairs(ctx, r)",
+	FormatGrid:       "inputs.Grid(ctx, r)",
+	FormatBlocks:     "inputs.Blocks(ctx, r)",
+	FormatLines:      "inputs.Lines(ctx, r)",
+}
+
+// Character classes counted in an input.
+var classes = []string{"digit", "lower", "upper", "space", "punct", "other"}
+
+var (
+	wordNumberPattern = regexp.MustCompile(`^[A-Za-z]+\s+-?[0-9]+$`)
+	numberPattern     = regexp.MustCompile(`-?[0-9]+`)
+)
+
+// Report on the shape of an input.
+type Report struct {
+	Bytes int
+	// Lines is the number of lines, of which BlankLines are empty.
+	Lines      int
+	BlankLines int
+	// Blocks is the number of runs of lines separated by blank lines.
+	Blocks int
+	// LineLengths counts the non-blank lines of each length.
+	LineLengths map[int]int
+	// Classes counts the characters of each class, leaving out newlines.
+	Classes map[string]int
+	// Matches counts the non-blank lines matching each line format.
+	Matches map[string]int
+	// Format of the input, one of the Format constants.
+	Format string
+	// Width of the lines of a binary input, in bits.
+	Width int
+	// Numbers counts the integers in the input, which range from Min to Max.
+	// Binary inputs are read in base 2.
+	Numbers int
+	Min     int
+	Max     int
+}
+
+// Inspect an input.
+func Inspect(r io.Reader) (Report, error) {
+	contents, err := io.ReadAll(r)
+	if err != nil {
+		return Report{}, err
+	}
+	report := Report{
+		Bytes:       len(contents),
+		LineLengths: map[int]int{},
+		Classes:     map[string]int{},
+		Matches:     map[string]int{},
+	}
+	for _, c := range string(contents) {
+		if c != '\n' {
+			report.Classes[class(c)]++
+		}
+	}
+	var lines []string
+	if len(contents) > 0 {
+		lines = strings.Split(strings.TrimSuffix(string(contents), "\n"), "\n")
+	}
+	report.Lines = len(lines)
+	inBlock := false
+	var nonBlank []string
+	for _, line := range lines {
+		if line == "" {
+			report.BlankLines++
+			inBlock = false
+			continue
+		}
+		if !inBlock {
+			report.Blocks++
+			inBlock = true
+		}
+		nonBlank = append(nonBlank, line)
+		report.LineLengths[len(line)]++
+		for _, format := range lineFormats {
+			if matches(format, line, len(nonBlank[0])) {
+				report.Matches[format]++
+			}
+		}
+	}
+	// Blocks are preferred to any line format, whose loader would drop the
+	// grouping.
+	report.Format = FormatLines
+	if report.Blocks > 1 {
+		report.Format = FormatBlocks
+	} else {
+		for _, format := range lineFormats {
+			if len(nonBlank) > 0 && report.Matches[format] == len(nonBlank) {
+				report.Format = format
+				break
+			}
+		}
+	}
+	report.countNumbers(string(contents), nonBlank)
+	return report, nil
+}
+
+// matches reports whether a line matches a line format, where width is the
+// length of the first line.
+func matches(format, line string, width int) bool {
+	switch format {
+	case FormatBinary:
+		// inputs.Binary only reads numbers that fit in an int.
+		return len(line) <= maxBinaryWidth && strings.Trim(line, "01") == ""
+	case FormatInts:
+		_, err := strconv.Atoi(line)
+		return err == nil
+	case FormatWordNumber:
+		return wordNumberPattern.MatchString(line)
+	case FormatGrid:
+		return len(line) == width && len(line) > 1 && !strings.ContainsAny(line, " \t")
+	}
+	return false
+}
+
+func (r *Report) countNumbers(contents string, lines []string) {
+	var numbers []int
+	if r.Format == FormatBinary {
+		for _, line := range lines {
+			if n, err := strconv.ParseInt(line, 2, 64); err == nil {
+				numbers = append(numbers, int(n))
+			}
+			if len(line) > r.Width {
+				r.Width = len(line)
+			}
+		}
+	} else {
+		for _, s := range numberPattern.FindAllString(contents, -1) {
+			if n, err := strconv.Atoi(s); err == nil {
+				numbers = append(numbers, n)
+			}
+		}
+	}
+	r.Numbers = len(numbers)
+	if len(numbers) == 0 {
+		return
+	}
+	r.Min, r.Max = math.MaxInt, math.MinInt
+	for _, n := range numbers {
+		r.Min = min(r.Min, n)
+		r.Max = max(r.Max, n)
+	}
+}
+
+func class(c rune) string {
+	switch {
+	case unicode.IsDigit(c):
+		return "digit"
+	case unicode.IsLower(c):
+		return "lower"
+	case unicode.IsUpper(c):
+		return "upper"
+	case unicode.IsSpace(c):
+		return "space"
+	case unicode.IsPunct(c) || unicode.IsSymbol(c):
+		return "punct"
+	default:
+		return "other"
+	}
+}
+
+// Loader suggests the function of the inputs package that reads the input.
+func (r Report) Loader() string {
+	return loaders[r.Format]
+}
+
+// Write the report as a table.
+func (r Report) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "bytes\t%d\n", r.Bytes)
+	fmt.Fprintf(tw, "lines\t%d (%d blank)\n", r.Lines, r.BlankLines)
+	fmt.Fprintf(tw, "blocks\t%d\n", r.Blocks)
+	lengths := make([]int, 0, len(r.LineLengths))
+	total := 0
+	for length, count := range r.LineLengths {
+		lengths = append(lengths, length)
+		total += length * count
+	}
+	sort.Ints(lengths)
+	if len(lengths) > 0 {
+		mean := float64(total) / float64(r.Lines-r.BlankLines)
+		fmt.Fprintf(tw, "line lengths\tmin %d, max %d, mean %.1f\n", lengths[0], lengths[len(lengths)-1], mean)
+		for _, length := range lengths {
+			fmt.Fprintf(tw, "  %d\t%d lines\n", length, r.LineLengths[length])
+		}
+	}
+	var counts []string
+	for _, class := range classes {
+		if count := r.Classes[class]; count > 0 {
+			counts = append(counts, fmt.Sprintf("%s %d", class, count))
+		}
+	}
+	fmt.Fprintf(tw, "characters\t%s\n", strings.Join(counts, ", "))
+	var matches []string
+	for _, format := range lineFormats {
+		matches = append(matches, fmt.Sprintf("%s %d/%d", format, r.Matches[format], r.Lines-r.BlankLines))
+	}
+	fmt.Fprintf(tw, "line formats\t%s\n", strings.Join(matches, ", "))
+	format := r.Format
+	if r.Format == FormatBinary {
+		format = fmt.Sprintf("%s, %d bits", format, r.Width)
+	}
+	fmt.Fprintf(tw, "format\t%s\n", format)
+	if r.Numbers > 0 {
+		fmt.Fprintf(tw, "numbers\t%d from %d to %d\n", r.Numbers, r.Min, r.Max)
+	} else {
+		fmt.Fprintf(tw, "numbers\tnone\n")
+	}
+	fmt.Fprintf(tw, "loader\t%s\n", r.Loader())
+	return tw.Flush()
+}
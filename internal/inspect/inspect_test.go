@@ -0,0 +1,73 @@
+package inspect_test
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/inspect"
+)
+
+func TestInspect(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		format  string
+		loader  string
+		numbers [3]int
+	}{
+		{name: "ints", input: "199\n200\n-8\n", format: inspect.FormatInts, loader: "inputs.Ints(ctx, r)", numbers: [3]int{3, -8, 200}},
+		{name: "blocks of ints", input: "199\n200\n\n-8\n", format: inspect.FormatBlocks, loader: "inputs.Blocks(ctx, r)", numbers: [3]int{3, -8, 200}},
+		{name: "binary", input: "00100\n11110\n10110\n", format: inspect.FormatBinary, loader: "inputs.Binary(ctx, r)", numbers: [3]int{3, 4, 30}},
+		{name: "binary of 63 bits", input: strings.Repeat("1", 63) + "\n", format: inspect.FormatBinary, loader: "inputs.Binary(ctx, r)", numbers: [3]int{1, math.MaxInt64, math.MaxInt64}},
+		{name: "binary too wide for an int", input: strings.Repeat("1", 64) + "\n" + strings.Repeat("0", 64) + "\n", format: inspect.FormatGrid, loader: "inputs.Grid(ctx, r)", numbers: [3]int{1, 0, 0}},
+		{name: "word number", input: "forward 5\ndown 5\nup 3\n", format: inspect.FormatWordNumber, loader: "inputs.Pairs(ctx, r)", numbers: [3]int{3, 3, 5}},
+		{name: "grid", input: "#.#\n.#.\n###\n", format: inspect.FormatGrid, loader: "inputs.Grid(ctx, r)"},
+		{name: "blocks", input: "7,4,9\n\n22 13 17\n8 2 23\n", format: inspect.FormatBlocks, loader: "inputs.Blocks(ctx, r)", numbers: [3]int{9, 2, 23}},
+		{name: "lines", input: "0,9 -> 5,9\n8,0 -> 0,8\n", format: inspect.FormatLines, loader: "inputs.Lines(ctx, r)", numbers: [3]int{8, 0, 9}},
+		{name: "empty", input: "", format: inspect.FormatLines, loader: "inputs.Lines(ctx, r)"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			report, err := inspect.Inspect(strings.NewReader(testCase.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Format != testCase.format {
+				t.Errorf("got: %v, want: %v", report.Format, testCase.format)
+			}
+			if got := report.Loader(); got != testCase.loader {
+				t.Errorf("got: %v, want: %v", got, testCase.loader)
+			}
+			if got := [3]int{report.Numbers, report.Min, report.Max}; got != testCase.numbers {
+				t.Errorf("got: %v, want: %v", got, testCase.numbers)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	report, err := inspect.Inspect(strings.NewReader("forward 5\n\ndown 10\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w bytes.Buffer
+	if err := report.Write(&w); err != nil {
+		t.Fatal(err)
+	}
+	want := "bytes         19\n" +
+		"lines         3 (1 blank)\n" +
+		"blocks        2\n" +
+		"line lengths  min 7, max 9, mean 8.0\n" +
+		"  7           1 lines\n" +
+		"  9           1 lines\n" +
+		"characters    digit 3, lower 11, space 2\n" +
+		"line formats  binary 0/2, ints 0/2, word number 2/2, grid 0/2\n" +
+		"format        blocks\n" +
+		"numbers       2 from 5 to 10\n" +
+		"loader        inputs.Blocks(ctx, r)\n"
+	if got := w.String(); got != want {
+		t.Errorf("got: %q, want: %q", got, want)
+	}
+}
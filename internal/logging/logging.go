@@ -0,0 +1,99 @@
+// Package logging provides the logger shared by the loaders and solvers of
+// every day. The logger travels in the context of a solve, so code that is
+// not given one logs nothing.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// LevelTrace is below debug, for values logged at every step of a solve.
+const LevelTrace = slog.LevelDebug - 4
+
+// Formats of the log records.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// ParseLevel parses trace, debug, info, warn or error.
+func ParseLevel(s string) (slog.Level, error) {
+	if strings.EqualFold(s, "trace") {
+		return LevelTrace, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: must be trace, debug, info, warn or error", s)
+	}
+	return level, nil
+}
+
+// ParseFormat checks that s is FormatText or FormatJSON.
+func ParseFormat(s string) (string, error) {
+	if s != FormatText && s != FormatJSON {
+		return "", fmt.Errorf("invalid log format %q: must be %s or %s", s, FormatText, FormatJSON)
+	}
+	return s, nil
+}
+
+// New returns a logger writing records at or above level to w in format.
+func New(w io.Writer, level slog.Level, format string) *slog.Logger {
+	options := &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.LevelKey && len(groups) == 0 && attr.Value.Any() == LevelTrace {
+				return slog.String(slog.LevelKey, "TRACE")
+			}
+			return attr
+		},
+	}
+	if format == FormatJSON {
+		return slog.New(slog.NewJSONHandler(w, options))
+	}
+	return slog.New(slog.NewTextHandler(w, options))
+}
+
+type loggerKey struct{}
+
+// NewContext returns a context carrying logger.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or a logger that discards
+// every record.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return discard
+}
+
+var discard = slog.New(discardHandler{})
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// Phase logs how long a phase of a solve took at debug level once the
+// returned function is called.
+//
+//	defer logging.Phase(ctx, "parse")()
+func Phase(ctx context.Context, name string) func() {
+	logger := FromContext(ctx)
+	if !logger.Enabled(ctx, slog.LevelDebug) {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		logger.DebugContext(ctx, "phase", "name", name, "duration", time.Since(start))
+	}
+}
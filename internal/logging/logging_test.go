@@ -0,0 +1,102 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/logging"
+)
+
+func TestParseLevel(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  slog.Level
+		valid bool
+	}{
+		{input: "trace", want: logging.LevelTrace, valid: true},
+		{input: "DEBUG", want: slog.LevelDebug, valid: true},
+		{input: "info", want: slog.LevelInfo, valid: true},
+		{input: "warn", want: slog.LevelWarn, valid: true},
+		{input: "error", want: slog.LevelError, valid: true},
+		{input: "loud", valid: false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.input, func(t *testing.T) {
+			got, err := logging.ParseLevel(testCase.input)
+			if (err == nil) != testCase.valid {
+				t.Fatalf("got error: %v, want valid: %v", err, testCase.valid)
+			}
+			if got != testCase.want {
+				t.Errorf("got: %v, want: %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, format := range []string{logging.FormatText, logging.FormatJSON} {
+		if _, err := logging.ParseFormat(format); err != nil {
+			t.Errorf("got: %v, want: %v", err, nil)
+		}
+	}
+	if _, err := logging.ParseFormat("xml"); err == nil {
+		t.Error("did not fail as expected")
+	}
+}
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{name: "text", format: logging.FormatText, want: "level=TRACE msg=step n=1"},
+		{name: "json", format: logging.FormatJSON, want: `"level":"TRACE","msg":"step","n":1`},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var w bytes.Buffer
+			logger := logging.New(&w, logging.LevelTrace, testCase.format)
+			logger.Log(context.Background(), logging.LevelTrace, "step", "n", 1)
+			if got := w.String(); !strings.Contains(got, testCase.want) {
+				t.Errorf("got: %q, want to contain: %q", got, testCase.want)
+			}
+		})
+	}
+	t.Run("drop records below the level", func(t *testing.T) {
+		var w bytes.Buffer
+		logger := logging.New(&w, slog.LevelInfo, logging.FormatText)
+		logger.Debug("hidden")
+		if w.Len() != 0 {
+			t.Errorf("got: %q, want nothing", w.String())
+		}
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	t.Run("return the logger in the context", func(t *testing.T) {
+		logger := logging.New(&bytes.Buffer{}, slog.LevelInfo, logging.FormatText)
+		ctx := logging.NewContext(context.Background(), logger)
+		if got := logging.FromContext(ctx); got != logger {
+			t.Errorf("got: %v, want: %v", got, logger)
+		}
+	})
+	t.Run("discard without a logger", func(t *testing.T) {
+		logger := logging.FromContext(context.Background())
+		if logger.Enabled(context.Background(), slog.LevelError) {
+			t.Error("got enabled, want discarded")
+		}
+	})
+}
+
+func TestPhase(t *testing.T) {
+	var w bytes.Buffer
+	ctx := logging.NewContext(context.Background(), logging.New(&w, slog.LevelDebug, logging.FormatText))
+	logging.Phase(ctx, "parse")()
+	if got, want := w.String(), "msg=phase name=parse duration="; !strings.Contains(got, want) {
+		t.Errorf("got: %q, want to contain: %q", got, want)
+	}
+}
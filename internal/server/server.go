@@ -0,0 +1,220 @@
+// Package server exposes the registered solvers over HTTP.
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+)
+
+// Config for the server.
+type Config struct {
+	// Year of the solvers served without a /years/{year} prefix.
+	Year int
+	// MaxBodyBytes is the largest puzzle input accepted.
+	MaxBodyBytes int64
+	// Timeout for solving a single puzzle input.
+	Timeout time.Duration
+	// MaxSolves is the most puzzle inputs solved at once, or one per CPU if
+	// zero. A solve that ignores its timeout keeps its place until it returns.
+	MaxSolves int
+}
+
+// DefaultConfig allows inputs far larger than any real puzzle input.
+var DefaultConfig = Config{
+	Year:         2021,
+	MaxBodyBytes: 1 << 20,
+	Timeout:      10 * time.Second,
+}
+
+// Day is the listing of a registered solver.
+type Day struct {
+	Year      int   `json:"year"`
+	Day       int   `json:"day"`
+	Parts     []int `json:"parts"`
+	Generator bool  `json:"generator"`
+}
+
+// Solution to one part of a puzzle input.
+type Solution struct {
+	Year       int            `json:"year"`
+	Day        int            `json:"day"`
+	Part       int            `json:"part"`
+	Answer     int            `json:"answer"`
+	Values     map[string]int `json:"values"`
+	DurationNS int64          `json:"duration_ns"`
+}
+
+// Error returned for a failed request.
+type Error struct {
+	Error string `json:"error"`
+}
+
+// New returns a handler serving the solvers in the aoc registry:
+//
+//	GET  /years/{year}/days                      lists the solvers of a year
+//	POST /years/{year}/days/{day}/parts/{part}   solves the puzzle input in the body
+//
+// The same paths without the /years/{year} prefix serve the year of the
+// config.
+func New(config Config) http.Handler {
+	maxSolves := config.MaxSolves
+	if maxSolves <= 0 {
+		maxSolves = runtime.GOMAXPROCS(0)
+	}
+	return handler{config: config, solves: make(chan struct{}, maxSolves)}
+}
+
+type handler struct {
+	config Config
+	// solves holds a place for each solve running.
+	solves chan struct{}
+}
+
+func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	year, segments, ok := parseYear(h.config.Year, r.URL.Path)
+	switch {
+	case !ok:
+		writeError(w, http.StatusNotFound, "not found")
+	case len(segments) == 1 && segments[0] == "days":
+		listDays(w, r, year)
+	case len(segments) == 4 && segments[0] == "days" && segments[2] == "parts":
+		h.solve(w, r, year, segments[1], segments[3])
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// parseYear splits a path into its segments after any /years/{year} prefix,
+// returning the year of the prefix or else defaultYear.
+func parseYear(defaultYear int, path string) (year int, segments []string, ok bool) {
+	segments = strings.Split(strings.Trim(path, "/"), "/")
+	if segments[0] != "years" {
+		return defaultYear, segments, true
+	}
+	if len(segments) < 2 {
+		return 0, nil, false
+	}
+	year, err := strconv.Atoi(segments[1])
+	if err != nil {
+		return 0, nil, false
+	}
+	return year, segments[2:], true
+}
+
+func listDays(w http.ResponseWriter, r *http.Request, year int) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	days := []Day{}
+	for _, solver := range aoc.YearSolvers(year) {
+		days = append(days, Day{
+			Year:      solver.Year,
+			Day:       solver.Day,
+			Parts:     []int{1, 2},
+			Generator: solver.NewGenerator != nil,
+		})
+	}
+	writeJSON(w, http.StatusOK, days)
+}
+
+func (h handler) solve(w http.ResponseWriter, r *http.Request, year int, daySegment, partSegment string) {
+	day, part, ok := parseDayPart(daySegment, partSegment)
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	solver, ok := aoc.Lookup(year, day)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no solver for day %d of %d", day, year))
+		return
+	}
+	input, err := io.ReadAll(io.LimitReader(r.Body, h.config.MaxBodyBytes+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if int64(len(input)) > h.config.MaxBodyBytes {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("input larger than %d bytes", h.config.MaxBodyBytes))
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), h.config.Timeout)
+	defer cancel()
+	ctx, values := aoc.WithValues(ctx)
+	start := time.Now()
+	answer, err := aoc.Solve(ctx, h.limit(solver.Part(part)), bytes.NewReader(input))
+	duration := time.Since(start)
+	switch {
+	case errors.Is(err, aoc.ErrTimeout):
+		writeError(w, http.StatusGatewayTimeout, fmt.Sprintf("day %d part %d timed out after %s", day, part, h.config.Timeout))
+		return
+	case errors.Is(err, context.Canceled):
+		return
+	case err != nil:
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("invalid input: %v", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, Solution{
+		Year:       year,
+		Day:        day,
+		Part:       part,
+		Answer:     answer,
+		Values:     values.Map(),
+		DurationNS: duration.Nanoseconds(),
+	})
+}
+
+// limit solve to run once it has a place in h.solves, waiting until ctx is
+// done for one. It keeps the place until solve returns, even after the
+// request has timed out, so that solves ignoring ctx cannot pile up.
+func (h handler) limit(solve aoc.SolveFunc) aoc.SolveFunc {
+	return func(ctx context.Context, input io.Reader) (int, error) {
+		select {
+		case h.solves <- struct{}{}:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+		defer func() { <-h.solves }()
+		return solve(ctx, input)
+	}
+}
+
+// parseDayPart parses the {day} and {part} of a solve path.
+func parseDayPart(daySegment, partSegment string) (day, part int, ok bool) {
+	day, err := strconv.Atoi(daySegment)
+	if err != nil {
+		return 0, 0, false
+	}
+	part, err = strconv.Atoi(partSegment)
+	if err != nil || part < 1 || part > 2 {
+		return 0, 0, false
+	}
+	return day, part, true
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, Error{Error: message})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
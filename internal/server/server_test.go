@@ -0,0 +1,276 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/server"
+	_ "github.com/dugword/advent-of-code-2021/years"
+)
+
+// slowDay is registered with a solver that only returns once cancelled.
+const slowDay = 100
+
+// stuckDay is registered with a solver that ignores its context and only
+// returns once it receives from unstick.
+const stuckDay = 101
+
+var unstick = make(chan struct{})
+
+func init() {
+	blocking := func(ctx context.Context, _ io.Reader) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	aoc.Register(aoc.Solver{Year: 2021, Day: slowDay, Part1: blocking, Part2: blocking})
+	stuck := func(context.Context, io.Reader) (int, error) {
+		<-unstick
+		return 0, nil
+	}
+	aoc.Register(aoc.Solver{Year: 2021, Day: stuckDay, Part1: stuck, Part2: stuck})
+}
+
+func newServer(t *testing.T, config server.Config) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(server.New(config))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func decode(t *testing.T, resp *http.Response, v interface{}) {
+	t.Helper()
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("got content type: %q, want: %q", got, "application/json")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListDays(t *testing.T) {
+	ts := newServer(t, server.DefaultConfig)
+	t.Run("list registered days", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/days")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("got status: %d, want: %d", resp.StatusCode, http.StatusOK)
+		}
+		var days []server.Day
+		decode(t, resp, &days)
+		want := server.Day{Year: 2021, Day: 1, Parts: []int{1, 2}, Generator: true}
+		if len(days) == 0 || !reflect.DeepEqual(days[0], want) {
+			t.Errorf("got: %+v, want first: %+v", days, want)
+		}
+	})
+	t.Run("list registered days of a year", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/years/2021/days")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var days []server.Day
+		decode(t, resp, &days)
+		if len(days) == 0 || days[0].Year != 2021 {
+			t.Errorf("got: %+v, want days of 2021", days)
+		}
+	})
+	t.Run("list no days of a year without solvers", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/years/2015/days")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var days []server.Day
+		decode(t, resp, &days)
+		if len(days) != 0 {
+			t.Errorf("got: %+v, want none", days)
+		}
+	})
+	t.Run("fail on wrong method", func(t *testing.T) {
+		resp, err := http.Post(ts.URL+"/days", "text/plain", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("got status: %d, want: %d", resp.StatusCode, http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func TestSolve(t *testing.T) {
+	input, err := os.ReadFile("../../years/2021/day03/testdata/input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := newServer(t, server.Config{
+		Year:         2021,
+		MaxBodyBytes: 64,
+		Timeout:      10 * time.Millisecond,
+	})
+	t.Run("solve an input", func(t *testing.T) {
+		resp, err := http.Post(ts.URL+"/days/3/parts/1", "text/plain", strings.NewReader(string(input)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("got status: %d, want: %d", resp.StatusCode, http.StatusOK)
+		}
+		var got server.Solution
+		decode(t, resp, &got)
+		if got.Year != 2021 || got.Day != 3 || got.Part != 1 {
+			t.Errorf("got %d day %d part %d, want 2021 day 3 part 1", got.Year, got.Day, got.Part)
+		}
+		gamma, epsilon := got.Values["gamma"], got.Values["epsilon"]
+		if got.Answer != gamma*epsilon || gamma == 0 {
+			t.Errorf("got answer %d from values %v", got.Answer, got.Values)
+		}
+		if got.DurationNS <= 0 {
+			t.Errorf("got duration: %d, want positive", got.DurationNS)
+		}
+	})
+	t.Run("solve an input of a year", func(t *testing.T) {
+		resp, err := http.Post(ts.URL+"/years/2021/days/3/parts/2", "text/plain", strings.NewReader(string(input)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("got status: %d, want: %d", resp.StatusCode, http.StatusOK)
+		}
+		var got server.Solution
+		decode(t, resp, &got)
+		if got.Year != 2021 || got.Day != 3 || got.Part != 2 {
+			t.Errorf("got %d day %d part %d, want 2021 day 3 part 2", got.Year, got.Day, got.Part)
+		}
+	})
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		error  string
+	}{
+		{
+			name:   "fail on invalid input",
+			method: http.MethodPost,
+			path:   "/days/3/parts/2",
+			body:   "blue\n",
+			status: http.StatusUnprocessableEntity,
+			error:  `invalid input: line 1: strconv.ParseInt: parsing "blue": invalid syntax`,
+		},
+		{
+			name:   "fail on oversized input",
+			method: http.MethodPost,
+			path:   "/days/1/parts/1",
+			body:   strings.Repeat("1\n", 33),
+			status: http.StatusRequestEntityTooLarge,
+			error:  "input larger than 64 bytes",
+		},
+		{
+			name:   "fail on timeout",
+			method: http.MethodPost,
+			path:   "/days/100/parts/1",
+			status: http.StatusGatewayTimeout,
+			error:  "day 100 part 1 timed out after 10ms",
+		},
+		{
+			name:   "fail on unknown day",
+			method: http.MethodPost,
+			path:   "/days/99/parts/1",
+			status: http.StatusNotFound,
+			error:  "no solver for day 99 of 2021",
+		},
+		{
+			name:   "fail on unknown year",
+			method: http.MethodPost,
+			path:   "/years/2015/days/1/parts/1",
+			status: http.StatusNotFound,
+			error:  "no solver for day 1 of 2015",
+		},
+		{
+			name:   "fail on invalid year",
+			method: http.MethodPost,
+			path:   "/years/first/days/1/parts/1",
+			status: http.StatusNotFound,
+			error:  "not found",
+		},
+		{
+			name:   "fail on unknown part",
+			method: http.MethodPost,
+			path:   "/days/1/parts/3",
+			status: http.StatusNotFound,
+			error:  "not found",
+		},
+		{
+			name:   "fail on wrong method",
+			method: http.MethodGet,
+			path:   "/days/1/parts/1",
+			status: http.StatusMethodNotAllowed,
+			error:  "method not allowed",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			req, err := http.NewRequest(testCase.method, ts.URL+testCase.path, strings.NewReader(testCase.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != testCase.status {
+				t.Errorf("got status: %d, want: %d", resp.StatusCode, testCase.status)
+			}
+			var got server.Error
+			decode(t, resp, &got)
+			if got.Error != testCase.error {
+				t.Errorf("got: %q, want: %q", got.Error, testCase.error)
+			}
+		})
+	}
+}
+
+func TestSolveLimit(t *testing.T) {
+	ts := newServer(t, server.Config{
+		Year:         2021,
+		MaxBodyBytes: 64,
+		Timeout:      50 * time.Millisecond,
+		MaxSolves:    1,
+	})
+	post := func(t *testing.T, path string) int {
+		t.Helper()
+		resp, err := http.Post(ts.URL+path, "text/plain", strings.NewReader("1\n2\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if got := post(t, "/days/101/parts/1"); got != http.StatusGatewayTimeout {
+		t.Fatalf("got status: %d, want: %d", got, http.StatusGatewayTimeout)
+	}
+	t.Run("wait for a solve that ignored its timeout", func(t *testing.T) {
+		if got := post(t, "/days/1/parts/1"); got != http.StatusGatewayTimeout {
+			t.Errorf("got status: %d, want: %d", got, http.StatusGatewayTimeout)
+		}
+	})
+	t.Run("solve once it returns", func(t *testing.T) {
+		unstick <- struct{}{}
+		if got := post(t, "/days/1/parts/1"); got != http.StatusOK {
+			t.Errorf("got status: %d, want: %d", got, http.StatusOK)
+		}
+	})
+}
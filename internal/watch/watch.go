@@ -0,0 +1,95 @@
+// Package watch detects changes to files by polling, so it works without any
+// file notification service.
+package watch
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileState is the part of a file's metadata that changes when it is written.
+type FileState struct {
+	Size    int64
+	ModTime time.Time
+}
+
+// Snapshot of the state of every file under a set of paths.
+type Snapshot map[string]FileState
+
+// Take a Snapshot of the files at paths, walking into directories. Paths that
+// do not exist are left out, so their creation is seen as a change.
+func Take(paths []string) (Snapshot, error) {
+	snapshot := Snapshot{}
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			snapshot[path] = FileState{
+				Size:    info.Size(),
+				ModTime: info.ModTime(),
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return snapshot, nil
+}
+
+// Changed lists the files added, removed or modified between s and other.
+func (s Snapshot) Changed(other Snapshot) []string {
+	var changed []string
+	for path, state := range s {
+		if otherState, ok := other[path]; !ok || !otherState.ModTime.Equal(state.ModTime) || otherState.Size != state.Size {
+			changed = append(changed, path)
+		}
+	}
+	for path := range other {
+		if _, ok := s[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
+
+// Watcher polls paths for changes.
+type Watcher struct {
+	Paths    []string
+	Interval time.Duration
+}
+
+// Wait until the files under the watched paths differ from last, returning
+// the new Snapshot and the files that changed.
+func (w Watcher) Wait(ctx context.Context, last Snapshot) (Snapshot, []string, error) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-ticker.C:
+		}
+		snapshot, err := Take(w.Paths)
+		if err != nil {
+			return nil, nil, err
+		}
+		if changed := last.Changed(snapshot); len(changed) > 0 {
+			return snapshot, changed, nil
+		}
+	}
+}
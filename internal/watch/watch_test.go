@@ -0,0 +1,105 @@
+package watch_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/dugword/advent-of-code-2021/internal/watch"
+)
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTake(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "testdata"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "main.go"), "package main")
+	writeFile(t, filepath.Join(dir, "testdata", "input"), "1\n")
+	snapshot, err := watch.Take([]string{dir, filepath.Join(dir, "missing")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for path := range snapshot {
+		got = append(got, path)
+	}
+	sort.Strings(got)
+	want := []string{
+		filepath.Join(dir, "main.go"),
+		filepath.Join(dir, "testdata", "input"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
+
+func TestChanged(t *testing.T) {
+	now := time.Now()
+	before := watch.Snapshot{
+		"same":    {Size: 1, ModTime: now},
+		"resized": {Size: 1, ModTime: now},
+		"touched": {Size: 1, ModTime: now},
+		"removed": {Size: 1, ModTime: now},
+	}
+	after := watch.Snapshot{
+		"same":    {Size: 1, ModTime: now},
+		"resized": {Size: 2, ModTime: now},
+		"touched": {Size: 1, ModTime: now.Add(time.Second)},
+		"added":   {Size: 1, ModTime: now},
+	}
+	got := before.Changed(after)
+	sort.Strings(got)
+	want := []string{"added", "removed", "resized", "touched"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
+
+func TestWait(t *testing.T) {
+	t.Run("return once a file changes", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "input")
+		writeFile(t, path, "1\n")
+		watcher := watch.Watcher{Paths: []string{dir}, Interval: time.Millisecond}
+		last, err := watch.Take(watcher.Paths)
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			if err := os.WriteFile(path, []byte("1\n2\n"), 0o644); err != nil {
+				t.Error(err)
+			}
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, changed, err := watcher.Wait(ctx, last)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(changed, []string{path}) {
+			t.Errorf("got: %v, want: %v", changed, []string{path})
+		}
+	})
+	t.Run("stop when the context is done", func(t *testing.T) {
+		watcher := watch.Watcher{Paths: []string{t.TempDir()}, Interval: time.Millisecond}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, _, got := watcher.Wait(ctx, watch.Snapshot{})
+		if !errors.Is(got, context.DeadlineExceeded) {
+			t.Errorf("got: %v, want: %v", got, context.DeadlineExceeded)
+		}
+	})
+}
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/years/yyyy/day0x"
+)
+
+func main() {
+	aoc.Main(day0x.Run)
+}
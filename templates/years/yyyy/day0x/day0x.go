@@ -0,0 +1,111 @@
+package day0x
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/inputs"
+	"github.com/dugword/advent-of-code-2021/internal/logging"
+)
+
+// example from the puzzle description.
+//
+//go:embed example
+var example embed.FS
+
+// Solver for day 0.
+var Solver = aoc.Solver{
+	Year:    0,
+	Day:     0,
+	Part1:   SolvePart1,
+	Part2:   SolvePart2,
+	Example: example,
+}
+
+func init() {
+	aoc.Register(Solver)
+}
+
+// LoadXXX from a file.
+func LoadXXX(filepath string) error {
+	file, err := inputs.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadXXX(file)
+}
+
+// ReadXXX from a reader.
+func ReadXXX(r io.Reader) error {
+	return readXXX(context.Background(), r)
+}
+
+func readXXX(ctx context.Context, r io.Reader) error {
+	defer logging.Phase(ctx, "read")()
+	lines, err := inputs.Lines(ctx, r)
+	if err != nil {
+	}
+	for _, line := range lines {
+	}
+}
+
+// WriteXXX to a writer in the format read by ReadXXX.
+func WriteXXX(w io.Writer) error {
+}
+
+// SolvePart1 from an input.
+func SolvePart1(ctx context.Context, input io.Reader) (int, error) {
+	_, err := readXXX(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+}
+
+// SolvePart2 from an input.
+func SolvePart2(ctx context.Context, input io.Reader) (int, error) {
+	_, err := readXXX(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+}
+
+// Run is an abstraction for main() that enables testing.
+func Run(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("day_01", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	inputFilepath := flags.String("input", "", "path to input file")
+	part2 := flags.Bool("part-2", false, "use part 2 logic")
+	var options aoc.Options
+	options.Register(flags)
+	if err := aoc.ParseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+	if *inputFilepath == "" {
+		return aoc.UsageError(errors.New("must provide an input file"))
+	}
+	part, solve := 1, SolvePart1
+	if *part2 {
+		part, solve = 2, SolvePart2
+	}
+	if options.CheckExample {
+		if err := aoc.CheckExample(context.Background(), Solver, part); err != nil {
+			return aoc.SolverError(err)
+		}
+	}
+	answer, err := options.Solve(solve, *inputFilepath, stderr)
+	switch {
+	case aoc.IsStopped(err):
+		return aoc.SolverError(fmt.Errorf("day 0 part %d: %w", part, err))
+	case aoc.ExitCode(err) == aoc.ExitInput:
+		return aoc.InputError(fmt.Errorf("invalid input file: %w", err))
+	case err != nil:
+		return err
+	}
+	return options.WriteAnswer(stdout, 0, 0, part, answer)
+}
@@ -0,0 +1,96 @@
+package day0x_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/aoctest"
+	"github.com/dugword/advent-of-code-2021/years/yyyy/day0x"
+)
+
+func TestLoadXXX(t *testing.T) {
+	t.Run("load xxx from file", func(t *testing.T) {
+		want := []int{
+			0b000111111001,
+			0b111011110110,
+			0b101111111000,
+		}
+		got, err := day0x.LoadXXX("./testdata/input")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got: %v, want: %v", got, want)
+		}
+	})
+	aoctest.LoaderRejects(t, day0x.LoadXXX)
+}
+
+func TestX(t *testing.T) {
+	testCases := []struct {
+		input       
+		want
+	}{
+		{
+			input: 
+			want:
+		},
+	}
+	for i, testCase := range testCases {
+		testName := fmt.Sprintf("test case %d", i)
+		t.Run(testName, func(t *testing.T) {
+			got := day0x.XXX()
+			if got!= testCase.want{
+				t.Errorf("got: %q, want: %q", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Run("run without failure", func(t *testing.T) {
+		got := aoctest.Run(t, day0x.Run, "day_0x", "-input", "./testdata/input")
+		aoctest.Golden(t, "./testdata/part_1.golden", got)
+	})
+	t.Run("run part 2 without failure", func(t *testing.T) {
+		got := aoctest.Run(t, day0x.Run, "day_0x", "-input", "./testdata/input", "-part-2")
+		aoctest.Golden(t, "./testdata/part_2.golden", got)
+	})
+	t.Run("print usage on help", func(t *testing.T) {
+		got := aoctest.RunError(t, day0x.Run, "day_0x", "-h")
+		aoctest.AssertExitCode(t, got, aoc.ExitOK)
+	})
+	t.Run("fail on missing argument", func(t *testing.T) {
+		got := aoctest.RunError(t, day0x.Run, "day_0x")
+		aoctest.AssertError(t, got, "must provide an input file")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+	t.Run("fail on invalid argument", func(t *testing.T) {
+		got := aoctest.RunError(t, day0x.Run, "day_0x", "-invalid")
+		aoctest.AssertError(t, got, "flag provided but not defined: -invalid")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+	t.Run("fail on invalid input file", func(t *testing.T) {
+		got := aoctest.RunError(t, day0x.Run, "day_0x", "-input", "./testdata/invalid")
+		aoctest.AssertErrorPrefix(t, got, "invalid input file:")
+		aoctest.AssertExitCode(t, got, aoc.ExitInput)
+	})
+	t.Run("fail on timeout", func(t *testing.T) {
+		got := aoctest.RunError(t, day0x.Run, "day_0x", "-input", "./testdata/input", "-timeout", "1ns")
+		if !errors.Is(got, aoc.ErrTimeout) {
+			t.Errorf("got: %v, want: %v", got, aoc.ErrTimeout)
+		}
+		aoctest.AssertExitCode(t, got, aoc.ExitSolver)
+	})
+}
+
+func TestCases(t *testing.T) {
+	aoctest.Cases(t, day0x.Solver)
+}
+
+func TestExample(t *testing.T) {
+	aoctest.Example(t, day0x.Solver)
+}
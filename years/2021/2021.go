@@ -0,0 +1,9 @@
+// Package year2021 registers the solver for every day of 2021 with the runner.
+package year2021
+
+import (
+	// Each day registers its solver when imported.
+	_ "github.com/dugword/advent-of-code-2021/years/2021/day01"
+	_ "github.com/dugword/advent-of-code-2021/years/2021/day02"
+	_ "github.com/dugword/advent-of-code-2021/years/2021/day03"
+)
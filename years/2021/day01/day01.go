@@ -0,0 +1,179 @@
+package day01
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/inputs"
+	"github.com/dugword/advent-of-code-2021/internal/logging"
+)
+
+// example from the puzzle description.
+//
+//go:embed example
+var example embed.FS
+
+// Solver for day 1.
+var Solver = aoc.Solver{
+	Year:  2021,
+	Day:   1,
+	Part1: SolvePart1,
+	Part2: SolvePart2,
+	NewGenerator: func() aoc.Generator {
+		return NewDepthSeries()
+	},
+	Example: example,
+}
+
+func init() {
+	aoc.Register(Solver)
+}
+
+// LoadDepthMeasurements from a file.
+func LoadDepthMeasurements(filepath string) ([]int, error) {
+	file, err := inputs.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadDepthMeasurements(file)
+}
+
+// ReadDepthMeasurements from a reader.
+func ReadDepthMeasurements(r io.Reader) ([]int, error) {
+	return readDepthMeasurements(context.Background(), r)
+}
+
+func readDepthMeasurements(ctx context.Context, r io.Reader) ([]int, error) {
+	defer logging.Phase(ctx, "read")()
+	return inputs.Ints(ctx, r)
+}
+
+// WriteDepthMeasurements to a writer in the format read by ReadDepthMeasurements.
+func WriteDepthMeasurements(w io.Writer, measurements []int) error {
+	for _, measurement := range measurements {
+		if _, err := fmt.Fprintf(w, "%d\n", measurement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CountMeasurementIncreases in the slice of measurements.
+func CountMeasurementIncreases(measurements []int) int {
+	return CountIncreases(measurements, 0)
+}
+
+// CountMeasurementWindowIncreases in the slice of measurements.
+func CountMeasurementWindowIncreases(measurements []int) int {
+	return CountWindowIncreases(measurements, 0)
+}
+
+// SolvePart1 counts the measurement increases in a measurements input.
+func SolvePart1(ctx context.Context, input io.Reader) (int, error) {
+	measurements, err := readDepthMeasurements(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	aoc.Record(ctx, "measurements", len(measurements))
+	defer logging.Phase(ctx, "count")()
+	return CountMeasurementIncreases(measurements), nil
+}
+
+// SolvePart2 counts the measurement window increases in a measurements input.
+func SolvePart2(ctx context.Context, input io.Reader) (int, error) {
+	measurements, err := readDepthMeasurements(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	aoc.Record(ctx, "measurements", len(measurements))
+	logWindowSums(ctx, measurements)
+	defer logging.Phase(ctx, "count")()
+	return CountMeasurementWindowIncreases(measurements), nil
+}
+
+// logWindowSums logs the sum of each window of measurements at trace level.
+func logWindowSums(ctx context.Context, measurements []int) {
+	logger := logging.FromContext(ctx)
+	if !logger.Enabled(ctx, logging.LevelTrace) {
+		return
+	}
+	for i := 3; i <= len(measurements); i++ {
+		logger.Log(ctx, logging.LevelTrace, "window", "end", i-1, "sum", sum(measurements[i-3:i]))
+	}
+}
+
+// Run is an abstraction for main() that enables testing.
+func Run(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("day_01", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	inputFilepath := flags.String("input", "", "path to measurements file")
+	part2 := flags.Bool("part-2", false, "use part 2 logic")
+	unit := flags.String("unit", "", "read decimal depths with optional units, converted to this unit: "+strings.Join(Units(), ", "))
+	epsilon := flags.Float64("epsilon", 0, "count only increases of more than this, in -unit")
+	var options aoc.Options
+	options.Register(flags)
+	if err := aoc.ParseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+	if *inputFilepath == "" {
+		return aoc.UsageError(errors.New("must provide a measurements file"))
+	}
+	part, solve := 1, SolvePart1
+	if *part2 {
+		part, solve = 2, SolvePart2
+	}
+	if *unit != "" || *epsilon != 0 {
+		if *unit == "" {
+			return aoc.UsageError(errors.New("must provide a unit with an epsilon"))
+		}
+		if *epsilon < 0 {
+			return aoc.UsageError(errors.New("epsilon must not be negative"))
+		}
+		if _, err := ParseMeasurement("0", *unit); err != nil {
+			return aoc.UsageError(err)
+		}
+		if options.CheckExample {
+			return aoc.UsageError(errors.New("cannot check the example with -unit"))
+		}
+		solve = SolvePart1Units(*unit, *epsilon)
+		if *part2 {
+			solve = SolvePart2Units(*unit, *epsilon)
+		}
+	}
+	if options.CheckExample {
+		if err := aoc.CheckExample(context.Background(), Solver, part); err != nil {
+			return aoc.SolverError(err)
+		}
+	}
+	count, err := options.Solve(solve, *inputFilepath, stderr)
+	switch {
+	case aoc.IsStopped(err):
+		return aoc.SolverError(fmt.Errorf("day 1 part %d: %w", part, err))
+	case aoc.ExitCode(err) == aoc.ExitInput:
+		return aoc.InputError(fmt.Errorf("invalid measurements file: %w", err))
+	case err != nil:
+		return err
+	}
+	return options.WriteAnswer(stdout, 2021, 1, part, count)
+}
+
+func sum[T Measurement](numbers []T) T {
+	var sum T
+	for _, n := range numbers {
+		sum += n
+	}
+	return sum
+}
@@ -0,0 +1,195 @@
+package day01_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/aoctest"
+	"github.com/dugword/advent-of-code-2021/years/2021/day01"
+)
+
+func TestLoadDepthMeasurements(t *testing.T) {
+	t.Run("load depth measurements from file", func(t *testing.T) {
+		want := []int{1, 2, 3}
+		got, err := day01.LoadDepthMeasurements("./testdata/input")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got: %v, want: %v", got, want)
+		}
+	})
+	aoctest.LoaderRejects(t, day01.LoadDepthMeasurements)
+}
+
+func TestCountMeasurementIncreases(t *testing.T) {
+	testCases := []struct {
+		input []int
+		want  int
+	}{
+		{
+			input: []int{1},
+			want:  0,
+		},
+		{
+			input: []int{1, 2},
+			want:  1,
+		},
+		{
+			input: []int{3, 2, 1},
+			want:  0,
+		},
+		{
+			input: []int{1, 3, 1},
+			want:  1,
+		},
+	}
+	for i, testCase := range testCases {
+		testName := fmt.Sprintf("test case %d", i)
+		t.Run(testName, func(t *testing.T) {
+			got := day01.CountMeasurementIncreases(testCase.input)
+			if got != testCase.want {
+				t.Errorf("got: %d, want: %d", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestCountMeasurementWindowIncreases(t *testing.T) {
+	testCases := []struct {
+		input []int
+		want  int
+	}{
+		{
+			input: []int{1, 2, 3},
+			want:  0,
+		},
+		{
+			input: []int{1, 2, 3, 4},
+			want:  1,
+		},
+		{
+			input: []int{4, 3, 2, 1},
+			want:  0,
+		},
+		{
+			input: []int{1, 3, 1, 4},
+			want:  1,
+		},
+	}
+	for i, testCase := range testCases {
+		testName := fmt.Sprintf("test case %d", i)
+		t.Run(testName, func(t *testing.T) {
+			got := day01.CountMeasurementWindowIncreases(testCase.input)
+			if got != testCase.want {
+				t.Errorf("got: %d, want: %d", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Run("run without failure", func(t *testing.T) {
+		got := aoctest.Run(t, day01.Run, "day_01", "-input", "./testdata/input")
+		aoctest.Golden(t, "./testdata/part_1.golden", got)
+	})
+	t.Run("run part 2 without failure", func(t *testing.T) {
+		got := aoctest.Run(t, day01.Run, "day_01", "-input", "./testdata/input", "-part-2")
+		aoctest.Golden(t, "./testdata/part_2.golden", got)
+	})
+	t.Run("log to stderr", func(t *testing.T) {
+		_, stderr := aoctest.RunStderr(t, day01.Run, "day_01", "-input", "./testdata/input", "-part-2", "-log-level", "trace", "-log-format", "json")
+		for _, want := range []string{`"msg":"read input","bytes":6,"lines":3,"skipped":0`, `"level":"TRACE","msg":"window","end":2,"sum":6`} {
+			if !strings.Contains(stderr, want) {
+				t.Errorf("got: %q, want to contain: %q", stderr, want)
+			}
+		}
+	})
+	t.Run("check the example before the input", func(t *testing.T) {
+		got := aoctest.Run(t, day01.Run, "day_01", "-input", "./testdata/input", "-check-example")
+		aoctest.Golden(t, "./testdata/part_1.golden", got)
+	})
+	t.Run("print usage on help", func(t *testing.T) {
+		got := aoctest.RunError(t, day01.Run, "day_01", "-h")
+		aoctest.AssertExitCode(t, got, aoc.ExitOK)
+	})
+	t.Run("fail on missing argument", func(t *testing.T) {
+		got := aoctest.RunError(t, day01.Run, "day_01")
+		aoctest.AssertError(t, got, "must provide a measurements file")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+	t.Run("fail on invalid argument", func(t *testing.T) {
+		got := aoctest.RunError(t, day01.Run, "day_01", "-invalid")
+		aoctest.AssertError(t, got, "flag provided but not defined: -invalid")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+	t.Run("fail on invalid measurements file", func(t *testing.T) {
+		got := aoctest.RunError(t, day01.Run, "day_01", "-input", "./testdata/invalid")
+		aoctest.AssertErrorPrefix(t, got, "invalid measurements file:")
+		aoctest.AssertExitCode(t, got, aoc.ExitInput)
+	})
+	t.Run("fail on unwritable profile", func(t *testing.T) {
+		cpuProfile := filepath.Join(t.TempDir(), "missing", "cpu.prof")
+		got := aoctest.RunError(t, day01.Run, "day_01", "-input", "./testdata/input", "-cpuprofile", cpuProfile)
+		if got == nil || strings.HasPrefix(got.Error(), "invalid measurements file:") {
+			t.Errorf("got: %v, want an error about the profile", got)
+		}
+		aoctest.AssertExitCode(t, got, aoc.ExitFailure)
+	})
+	t.Run("fail on timeout", func(t *testing.T) {
+		got := aoctest.RunError(t, day01.Run, "day_01", "-input", "./testdata/input", "-timeout", "1ns")
+		if !errors.Is(got, aoc.ErrTimeout) {
+			t.Errorf("got: %v, want: %v", got, aoc.ErrTimeout)
+		}
+		aoctest.AssertExitCode(t, got, aoc.ExitSolver)
+	})
+}
+
+func TestExample(t *testing.T) {
+	aoctest.Example(t, day01.Solver)
+}
+
+func TestCases(t *testing.T) {
+	aoctest.Cases(t, day01.Solver)
+}
+
+func FuzzReadDepthMeasurements(f *testing.F) {
+	aoctest.AddSeedCorpus(f)
+	f.Fuzz(func(t *testing.T, input []byte) {
+		measurements, err := day01.ReadDepthMeasurements(bytes.NewReader(input))
+		if err != nil {
+			return
+		}
+		var buf bytes.Buffer
+		if err := day01.WriteDepthMeasurements(&buf, measurements); err != nil {
+			t.Fatal(err)
+		}
+		got, err := day01.ReadDepthMeasurements(&buf)
+		if err != nil {
+			t.Fatalf("failed to read formatted measurements: %v", err)
+		}
+		if !reflect.DeepEqual(got, measurements) {
+			t.Errorf("got: %v, want: %v", got, measurements)
+		}
+	})
+}
+
+func FuzzSolve(f *testing.F) {
+	aoctest.AddSeedCorpus(f)
+	f.Fuzz(func(t *testing.T, input []byte) {
+		_, readErr := day01.ReadDepthMeasurements(bytes.NewReader(input))
+		for i, solve := range []aoc.SolveFunc{day01.SolvePart1, day01.SolvePart2} {
+			_, err := solve(context.Background(), bytes.NewReader(input))
+			if (err == nil) != (readErr == nil) {
+				t.Errorf("part %d got: %v, want the error of reading: %v", i+1, err, readErr)
+			}
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package day01
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"math"
+	"math/rand"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+)
+
+// DepthSeries generates sonar sweeps that drift deeper by Trend per reading
+// with normally distributed Noise.
+type DepthSeries struct {
+	Length int
+	Start  int
+	Trend  float64
+	Noise  float64
+}
+
+// NewDepthSeries with parameters resembling a real puzzle input.
+func NewDepthSeries() *DepthSeries {
+	return &DepthSeries{
+		Length: 2000,
+		Start:  150,
+		Trend:  3,
+		Noise:  10,
+	}
+}
+
+// Register the flags for each parameter.
+func (d *DepthSeries) Register(flags *flag.FlagSet) {
+	flags.IntVar(&d.Length, "n", d.Length, "number of measurements")
+	flags.IntVar(&d.Start, "start", d.Start, "depth of the first measurement")
+	flags.Float64Var(&d.Trend, "trend", d.Trend, "mean change in depth per measurement")
+	flags.Float64Var(&d.Noise, "noise", d.Noise, "standard deviation of the change in depth")
+}
+
+// Measurements generated from rng. Depths never go above the surface.
+func (d *DepthSeries) Measurements(rng *rand.Rand) ([]int, error) {
+	if d.Length < 0 {
+		return nil, errors.New("length must not be negative")
+	}
+	if d.Noise < 0 {
+		return nil, errors.New("noise must not be negative")
+	}
+	measurements := make([]int, d.Length)
+	depth := float64(d.Start)
+	for i := range measurements {
+		measurements[i] = int(math.Max(0, math.Round(depth)))
+		depth += d.Trend + d.Noise*rng.NormFloat64()
+	}
+	return measurements, nil
+}
+
+// Generate a measurements file.
+func (d *DepthSeries) Generate(w io.Writer, rng *rand.Rand) (answers [2]int, err error) {
+	measurements, err := d.Measurements(rng)
+	if err != nil {
+		return answers, err
+	}
+	if err := WriteDepthMeasurements(w, measurements); err != nil {
+		return answers, err
+	}
+	return referenceAnswers(measurements), nil
+}
+
+// referenceAnswers relies on consecutive windows sharing two measurements,
+// so a window sum increases exactly when the measurement entering the window
+// is larger than the one leaving it.
+func referenceAnswers(measurements []int) (answers [2]int) {
+	for i := range measurements {
+		if i >= 1 && measurements[i] > measurements[i-1] {
+			answers[0]++
+		}
+		if i >= 3 && measurements[i] > measurements[i-3] {
+			answers[1]++
+		}
+	}
+	return answers
+}
+
+var _ aoc.Generator = (*DepthSeries)(nil)
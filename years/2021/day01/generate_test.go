@@ -0,0 +1,66 @@
+package day01_test
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/years/2021/day01"
+)
+
+func TestDepthSeriesGenerate(t *testing.T) {
+	testCases := []*day01.DepthSeries{
+		day01.NewDepthSeries(),
+		{Length: 0},
+		{Length: 3, Start: 10},
+		{Length: 500, Start: 0, Trend: -5, Noise: 1},
+		{Length: 500, Start: 100, Trend: 0, Noise: 50},
+	}
+	for i, generator := range testCases {
+		for seed := int64(0); seed < 10; seed++ {
+			var buf bytes.Buffer
+			answers, err := generator.Generate(&buf, rand.New(rand.NewSource(seed)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			measurements, err := day01.ReadDepthMeasurements(&buf)
+			if err != nil {
+				t.Fatalf("test case %d: generated invalid input: %v", i, err)
+			}
+			if len(measurements) != generator.Length {
+				t.Errorf("test case %d: got %d measurements, want %d", i, len(measurements), generator.Length)
+			}
+			got := [2]int{
+				day01.CountMeasurementIncreases(measurements),
+				day01.CountMeasurementWindowIncreases(measurements),
+			}
+			if got != answers {
+				t.Errorf("test case %d seed %d: got: %v, want: %v", i, seed, got, answers)
+			}
+		}
+	}
+}
+
+func TestDepthSeriesMeasurements(t *testing.T) {
+	t.Run("generate the same measurements from the same seed", func(t *testing.T) {
+		generator := day01.NewDepthSeries()
+		a, _ := generator.Measurements(rand.New(rand.NewSource(1)))
+		b, _ := generator.Measurements(rand.New(rand.NewSource(1)))
+		if !reflect.DeepEqual(a, b) {
+			t.Error("measurements differ for the same seed")
+		}
+	})
+	t.Run("fail on negative length", func(t *testing.T) {
+		generator := &day01.DepthSeries{Length: -1}
+		if _, got := generator.Measurements(rand.New(rand.NewSource(1))); got == nil {
+			t.Error("did not fail as expected")
+		}
+	})
+	t.Run("fail on negative noise", func(t *testing.T) {
+		generator := &day01.DepthSeries{Length: 1, Noise: -1}
+		if _, got := generator.Measurements(rand.New(rand.NewSource(1))); got == nil {
+			t.Error("did not fail as expected")
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package day01_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/difftest"
+	"github.com/dugword/advent-of-code-2021/years/2021/day01"
+)
+
+// naiveWindowIncreases sums every window up front, then compares each sum
+// with the one before it.
+func naiveWindowIncreases(measurements []int) int {
+	var sums []int
+	for i := 0; i+3 <= len(measurements); i++ {
+		sums = append(sums, measurements[i]+measurements[i+1]+measurements[i+2])
+	}
+	count := 0
+	for i := 1; i < len(sums); i++ {
+		if sums[i] > sums[i-1] {
+			count++
+		}
+	}
+	return count
+}
+
+func randomMeasurements(rng *rand.Rand) []int {
+	generator := &day01.DepthSeries{
+		Length: rng.Intn(50),
+		Start:  rng.Intn(1000),
+		Trend:  rng.Float64()*10 - 5,
+		Noise:  rng.Float64() * 20,
+	}
+	measurements, err := generator.Measurements(rng)
+	if err != nil {
+		panic(err)
+	}
+	return measurements
+}
+
+func TestDifferentialCountMeasurementWindowIncreases(t *testing.T) {
+	config := difftest.Config{Iterations: 5000}
+	candidate := func(measurements []int) int {
+		return day01.CountMeasurementWindowIncreases(append([]int(nil), measurements...))
+	}
+	difftest.Check(t, config, randomMeasurements, difftest.ShrinkSlice[int], naiveWindowIncreases, candidate)
+}
+
+func TestDifferentialFloatIncreases(t *testing.T) {
+	config := difftest.Config{Iterations: 2000}
+	floats := func(measurements []int) []float64 {
+		converted := make([]float64, len(measurements))
+		for i, measurement := range measurements {
+			converted[i] = float64(measurement)
+		}
+		return converted
+	}
+	t.Run("count increases", func(t *testing.T) {
+		candidate := func(measurements []int) int {
+			return day01.CountIncreases(floats(measurements), 0)
+		}
+		difftest.Check(t, config, randomMeasurements, difftest.ShrinkSlice[int], day01.CountMeasurementIncreases, candidate)
+	})
+	t.Run("count window increases", func(t *testing.T) {
+		candidate := func(measurements []int) int {
+			return day01.CountWindowIncreases(floats(measurements), 0)
+		}
+		difftest.Check(t, config, randomMeasurements, difftest.ShrinkSlice[int], naiveWindowIncreases, candidate)
+	})
+}
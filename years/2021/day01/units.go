@@ -0,0 +1,191 @@
+package day01
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/inputs"
+	"github.com/dugword/advent-of-code-2021/internal/logging"
+)
+
+// Measurement is a depth measured in whole or fractional units.
+type Measurement interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~float32 | ~float64
+}
+
+// units are the lengths of each unit of depth in meters.
+var units = map[string]float64{
+	"mm":  0.001,
+	"cm":  0.01,
+	"m":   1,
+	"km":  1000,
+	"in":  0.0254,
+	"ft":  0.3048,
+	"yd":  0.9144,
+	"ftm": 1.8288,
+}
+
+// Units of depth that measurements can be written in.
+func Units() []string {
+	names := make([]string, 0, len(units))
+	for name := range units {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseMeasurement of a decimal depth with an optional unit suffix, such as
+// "1234.5m" or "40 ft", converted to unit. Depths without a suffix are already
+// in unit.
+func ParseMeasurement(s, unit string) (float64, error) {
+	to, ok := units[unit]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit: %q", unit)
+	}
+	number := strings.TrimRightFunc(s, unicode.IsLetter)
+	from := to
+	if suffix := s[len(number):]; suffix != "" {
+		if from, ok = units[suffix]; !ok {
+			return 0, fmt.Errorf("unknown unit %q in %q", suffix, s)
+		}
+	}
+	measurement, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if err != nil {
+		return 0, err
+	}
+	// Converting between different units only, since even multiplying and
+	// dividing by the same length can round. Checked after converting, which
+	// can overflow.
+	if from != to {
+		measurement = measurement * from / to
+	}
+	if math.IsInf(measurement, 0) || math.IsNaN(measurement) {
+		return 0, fmt.Errorf("invalid depth: %q", s)
+	}
+	return measurement, nil
+}
+
+// LoadMeasurements from a file of depths with optional units, converted to
+// unit.
+func LoadMeasurements(filepath, unit string) ([]float64, error) {
+	file, err := inputs.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadMeasurements(file, unit)
+}
+
+// ReadMeasurements from a reader of depths with optional units, converted to
+// unit.
+func ReadMeasurements(r io.Reader, unit string) ([]float64, error) {
+	return readMeasurements(context.Background(), r, unit)
+}
+
+func readMeasurements(ctx context.Context, r io.Reader, unit string) ([]float64, error) {
+	defer logging.Phase(ctx, "read")()
+	lines, err := inputs.Lines(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	measurements := make([]float64, 0, len(lines))
+	for _, line := range lines {
+		measurement, err := ParseMeasurement(line, unit)
+		if err != nil {
+			return nil, err
+		}
+		measurements = append(measurements, measurement)
+	}
+	return measurements, nil
+}
+
+// CountIncreases of more than epsilon, which must not be negative, in the
+// slice of measurements.
+func CountIncreases[T Measurement](measurements []T, epsilon T) int {
+	count := 0
+	if len(measurements) < 2 {
+		return count
+	}
+	lastMeasurement := measurements[0]
+	for _, measurement := range measurements[1:] {
+		if increased(lastMeasurement, measurement, epsilon) {
+			count++
+		}
+		lastMeasurement = measurement
+	}
+	return count
+}
+
+// CountWindowIncreases of more than epsilon, which must not be negative, in
+// the sums of each three measurements in the slice.
+func CountWindowIncreases[T Measurement](measurements []T, epsilon T) int {
+	count := 0
+	if len(measurements) < 4 {
+		return count
+	}
+	lastSum := sum(measurements[:3])
+	for i := 4; i <= len(measurements); i++ {
+		windowSum := sum(measurements[i-3 : i])
+		if increased(lastSum, windowSum, epsilon) {
+			count++
+		}
+		lastSum = windowSum
+	}
+	return count
+}
+
+// increased reports whether to is more than epsilon above from, without
+// overflowing integer measurements near their limits.
+func increased[T Measurement](from, to, epsilon T) bool {
+	switch {
+	case to <= from:
+		return false
+	case from < 0 && to >= 0:
+		// to-from could overflow, but from+epsilon cannot.
+		return to > from+epsilon
+	default:
+		return to-from > epsilon
+	}
+}
+
+// SolvePart1Units counts the increases of more than epsilon in a measurements
+// input of depths with optional units, converted to unit.
+func SolvePart1Units(unit string, epsilon float64) aoc.SolveFunc {
+	return func(ctx context.Context, input io.Reader) (int, error) {
+		measurements, err := readMeasurements(ctx, input, unit)
+		if err != nil {
+			return 0, err
+		}
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		aoc.Record(ctx, "measurements", len(measurements))
+		defer logging.Phase(ctx, "count")()
+		return CountIncreases(measurements, epsilon), nil
+	}
+}
+
+// SolvePart2Units counts the window increases of more than epsilon in a
+// measurements input of depths with optional units, converted to unit.
+func SolvePart2Units(unit string, epsilon float64) aoc.SolveFunc {
+	return func(ctx context.Context, input io.Reader) (int, error) {
+		measurements, err := readMeasurements(ctx, input, unit)
+		if err != nil {
+			return 0, err
+		}
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		aoc.Record(ctx, "measurements", len(measurements))
+		defer logging.Phase(ctx, "count")()
+		return CountWindowIncreases(measurements, epsilon), nil
+	}
+}
@@ -0,0 +1,208 @@
+package day01_test
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/aoctest"
+	"github.com/dugword/advent-of-code-2021/years/2021/day01"
+)
+
+func TestParseMeasurement(t *testing.T) {
+	testCases := []struct {
+		input string
+		unit  string
+		want  float64
+	}{
+		{input: "1234.5m", unit: "m", want: 1234.5},
+		{input: "1234.5", unit: "m", want: 1234.5},
+		{input: "40ft", unit: "ft", want: 40},
+		{input: "40 ft", unit: "in", want: 480},
+		{input: "1km", unit: "m", want: 1000},
+		{input: "-250cm", unit: "m", want: -2.5},
+		{input: "1e3mm", unit: "m", want: 1},
+		{input: "1ftm", unit: "ft", want: 6},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.input+" in "+testCase.unit, func(t *testing.T) {
+			got, err := day01.ParseMeasurement(testCase.input, testCase.unit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := got - testCase.want; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("got: %v, want: %v", got, testCase.want)
+			}
+		})
+	}
+	for _, input := range []string{"3.3", "3.3ft"} {
+		t.Run("keep "+input+" in ft exact", func(t *testing.T) {
+			got, err := day01.ParseMeasurement(input, "ft")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != 3.3 {
+				t.Errorf("got: %v, want: %v", got, 3.3)
+			}
+		})
+	}
+	for _, input := range []string{"", "m", "12parsecs", "1e", "NaN", "Inf", "1.2.3m", "1e308km"} {
+		t.Run("fail on "+input, func(t *testing.T) {
+			if _, got := day01.ParseMeasurement(input, "m"); got == nil {
+				t.Error("did not fail as expected")
+			}
+		})
+	}
+	t.Run("fail on unknown unit", func(t *testing.T) {
+		if _, got := day01.ParseMeasurement("1m", "furlong"); got == nil {
+			t.Error("did not fail as expected")
+		}
+	})
+}
+
+func TestLoadMeasurements(t *testing.T) {
+	t.Run("load measurements from file", func(t *testing.T) {
+		want := []float64{199, 200, 208, 210, 199.9488}
+		got, err := day01.LoadMeasurements("./testdata/units", "m")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("got: %v, want: %v", got, want)
+		}
+		for i := range got {
+			if diff := got[i] - want[i]; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("got: %v, want: %v", got, want)
+			}
+		}
+	})
+	aoctest.LoaderRejects(t, func(filepath string) ([]float64, error) {
+		return day01.LoadMeasurements(filepath, "m")
+	})
+}
+
+func TestCountIncreases(t *testing.T) {
+	t.Run("count integer increases", func(t *testing.T) {
+		measurements := []int{199, 200, 208, 210, 200, 207, 240, 269, 260, 263}
+		got := [2]int{day01.CountIncreases(measurements, 0), day01.CountWindowIncreases(measurements, 0)}
+		if want := [2]int{7, 5}; got != want {
+			t.Errorf("got: %v, want: %v", got, want)
+		}
+	})
+	testCases := []struct {
+		name    string
+		epsilon float64
+		want    [2]int
+	}{
+		{name: "every increase", epsilon: 0, want: [2]int{3, 2}},
+		{name: "increases over epsilon", epsilon: 0.05, want: [2]int{2, 2}},
+		{name: "window increases over epsilon", epsilon: 0.195, want: [2]int{0, 1}},
+		{name: "no increases over epsilon", epsilon: 1, want: [2]int{0, 0}},
+	}
+	measurements := []float64{10, 10.01, 10.1, 10.2, 10.2}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got := [2]int{
+				day01.CountIncreases(measurements, testCase.epsilon),
+				day01.CountWindowIncreases(measurements, testCase.epsilon),
+			}
+			if got != testCase.want {
+				t.Errorf("got: %v, want: %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestCountIncreasesNearLimits(t *testing.T) {
+	testCases := []struct {
+		name         string
+		measurements []int
+		epsilon      int
+		want         int
+	}{
+		{name: "from the minimum to the maximum", measurements: []int{math.MinInt, math.MaxInt}, want: 1},
+		{name: "from the maximum to the minimum", measurements: []int{math.MaxInt, math.MinInt}, want: 0},
+		{name: "up to the maximum", measurements: []int{math.MaxInt - 1, math.MaxInt}, want: 1},
+		{name: "up to the maximum within epsilon", measurements: []int{math.MaxInt - 1, math.MaxInt}, epsilon: 1, want: 0},
+		{name: "across zero within epsilon", measurements: []int{-1, 1}, epsilon: 2, want: 0},
+		{name: "across zero over epsilon", measurements: []int{-1, 2}, epsilon: 2, want: 1},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := day01.CountIncreases(testCase.measurements, testCase.epsilon); got != testCase.want {
+				t.Errorf("got: %v, want: %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestRunUnits(t *testing.T) {
+	t.Run("run with units", func(t *testing.T) {
+		got := aoctest.Run(t, day01.Run, "day_01", "-input", "./testdata/units", "-unit", "m")
+		aoctest.Golden(t, "./testdata/units_part_1.golden", got)
+		got = aoctest.Run(t, day01.Run, "day_01", "-input", "./testdata/units", "-unit", "m", "-part-2")
+		aoctest.Golden(t, "./testdata/units_part_2.golden", got)
+	})
+	t.Run("run integers with units", func(t *testing.T) {
+		got := aoctest.Run(t, day01.Run, "day_01", "-input", "./testdata/input", "-unit", "m")
+		aoctest.Golden(t, "./testdata/part_1.golden", got)
+		got = aoctest.Run(t, day01.Run, "day_01", "-input", "./testdata/input", "-unit", "m", "-part-2")
+		aoctest.Golden(t, "./testdata/part_2.golden", got)
+	})
+	t.Run("fail on units without -unit", func(t *testing.T) {
+		got := aoctest.RunError(t, day01.Run, "day_01", "-input", "./testdata/units")
+		aoctest.AssertErrorPrefix(t, got, "invalid measurements file:")
+		aoctest.AssertExitCode(t, got, aoc.ExitInput)
+	})
+	t.Run("fail on unknown unit", func(t *testing.T) {
+		got := aoctest.RunError(t, day01.Run, "day_01", "-input", "./testdata/units", "-unit", "furlong")
+		aoctest.AssertError(t, got, `unknown unit: "furlong"`)
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+	t.Run("fail on negative epsilon", func(t *testing.T) {
+		got := aoctest.RunError(t, day01.Run, "day_01", "-input", "./testdata/units", "-unit", "m", "-epsilon", "-1")
+		aoctest.AssertError(t, got, "epsilon must not be negative")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+	t.Run("fail on epsilon without unit", func(t *testing.T) {
+		got := aoctest.RunError(t, day01.Run, "day_01", "-input", "./testdata/units", "-epsilon", "0.5")
+		aoctest.AssertError(t, got, "must provide a unit with an epsilon")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+	t.Run("fail on checking the example with a unit", func(t *testing.T) {
+		got := aoctest.RunError(t, day01.Run, "day_01", "-input", "./testdata/units", "-unit", "m", "-check-example")
+		aoctest.AssertError(t, got, "cannot check the example with -unit")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+}
+
+func FuzzReadMeasurements(f *testing.F) {
+	aoctest.AddSeedCorpus(f)
+	contents, err := os.ReadFile("./testdata/units")
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(contents)
+	f.Fuzz(func(t *testing.T, input []byte) {
+		measurements, err := day01.ReadMeasurements(bytes.NewReader(input), "m")
+		if err != nil {
+			return
+		}
+		var buf bytes.Buffer
+		for _, measurement := range measurements {
+			fmt.Fprintf(&buf, "%sm\n", strconv.FormatFloat(measurement, 'g', -1, 64))
+		}
+		got, err := day01.ReadMeasurements(&buf, "m")
+		if err != nil {
+			t.Fatalf("failed to read formatted measurements: %v", err)
+		}
+		if !reflect.DeepEqual(got, measurements) {
+			t.Errorf("got: %v, want: %v", got, measurements)
+		}
+	})
+}
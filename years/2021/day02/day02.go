@@ -0,0 +1,213 @@
+package day02
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/inputs"
+	"github.com/dugword/advent-of-code-2021/internal/logging"
+)
+
+// Command to give the submarine, includes a direction and a value.
+type Command struct {
+	Direction string
+	Value     int
+}
+
+// example from the puzzle description.
+//
+//go:embed example
+var example embed.FS
+
+// Solver for day 2.
+var Solver = aoc.Solver{
+	Year:  2021,
+	Day:   2,
+	Part1: SolvePart1,
+	Part2: SolvePart2,
+	NewGenerator: func() aoc.Generator {
+		return NewCommandSequence()
+	},
+	Example: example,
+}
+
+func init() {
+	aoc.Register(Solver)
+}
+
+// LoadCommands from a file.
+func LoadCommands(filepath string) ([]Command, error) {
+	file, err := inputs.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadCommands(file)
+}
+
+// ReadCommands from a reader.
+func ReadCommands(r io.Reader) ([]Command, error) {
+	return readCommands(context.Background(), r)
+}
+
+func readCommands(ctx context.Context, r io.Reader) ([]Command, error) {
+	defer logging.Phase(ctx, "read")()
+	pairs, err := inputs.Pairs(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	commands := make([]Command, 0, len(pairs))
+	for _, pair := range pairs {
+		switch {
+		case pair.Word != "down" && pair.Word != "forward" && pair.Word != "up":
+			return nil, fmt.Errorf("invalid command: %q", pair.Word)
+		case pair.Number < 0:
+			return nil, fmt.Errorf("invalid command: %s %d", pair.Word, pair.Number)
+		}
+		commands = append(commands, Command{Direction: pair.Word, Value: pair.Number})
+	}
+	return commands, nil
+}
+
+// WriteCommands to a writer in the format read by ReadCommands.
+func WriteCommands(w io.Writer, commands []Command) error {
+	for _, command := range commands {
+		if _, err := fmt.Fprintf(w, "%s %d\n", command.Direction, command.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CalculatePosition from a slice of Commands
+func CalculatePosition(commands []Command) (horizontal, depth int) {
+	for _, command := range commands {
+		switch command.Direction {
+		case "down":
+			depth += command.Value
+		case "up":
+			depth -= command.Value
+		case "forward":
+			horizontal += command.Value
+		}
+	}
+	return
+}
+
+// CalculatePositionWithAim from a slice of Commands
+func CalculatePositionWithAim(commands []Command) (horizontal, depth int) {
+	aim := 0
+	for _, command := range commands {
+		switch command.Direction {
+		case "down":
+			aim += command.Value
+		case "up":
+			aim -= command.Value
+		case "forward":
+			depth += aim * command.Value
+			horizontal += command.Value
+		}
+	}
+	return
+}
+
+// SolvePart1 multiplies the final horizontal position and depth from a
+// commands input.
+func SolvePart1(ctx context.Context, input io.Reader) (int, error) {
+	commands, err := readCommands(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	done := logging.Phase(ctx, "calculate")
+	horizontal, depth := CalculatePosition(commands)
+	done()
+	aoc.Record(ctx, "horizontal", horizontal)
+	aoc.Record(ctx, "depth", depth)
+	return horizontal * depth, nil
+}
+
+// SolvePart2 multiplies the final horizontal position and depth from a
+// commands input, taking aim into account.
+func SolvePart2(ctx context.Context, input io.Reader) (int, error) {
+	commands, err := readCommands(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	logAim(ctx, commands)
+	done := logging.Phase(ctx, "calculate")
+	horizontal, depth := CalculatePositionWithAim(commands)
+	done()
+	aoc.Record(ctx, "horizontal", horizontal)
+	aoc.Record(ctx, "depth", depth)
+	return horizontal * depth, nil
+}
+
+// logAim logs the aim after each command at trace level, and the final aim at
+// debug level.
+func logAim(ctx context.Context, commands []Command) {
+	logger := logging.FromContext(ctx)
+	if !logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	trace := logger.Enabled(ctx, logging.LevelTrace)
+	aim := 0
+	for i, command := range commands {
+		switch command.Direction {
+		case "down":
+			aim += command.Value
+		case "up":
+			aim -= command.Value
+		}
+		if trace {
+			logger.Log(ctx, logging.LevelTrace, "aim", "command", i, "aim", aim)
+		}
+	}
+	logger.DebugContext(ctx, "final aim", "aim", aim)
+}
+
+// Run is an abstraction for main() that enables testing.
+func Run(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("day_01", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	inputFilepath := flags.String("input", "", "path to input file")
+	part2 := flags.Bool("part-2", false, "use part 2 logic")
+	var options aoc.Options
+	options.Register(flags)
+	if err := aoc.ParseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+	if *inputFilepath == "" {
+		return aoc.UsageError(errors.New("must provide an input file"))
+	}
+	part, solve := 1, SolvePart1
+	if *part2 {
+		part, solve = 2, SolvePart2
+	}
+	if options.CheckExample {
+		if err := aoc.CheckExample(context.Background(), Solver, part); err != nil {
+			return aoc.SolverError(err)
+		}
+	}
+	position, err := options.Solve(solve, *inputFilepath, stderr)
+	switch {
+	case aoc.IsStopped(err):
+		return aoc.SolverError(fmt.Errorf("day 2 part %d: %w", part, err))
+	case aoc.ExitCode(err) == aoc.ExitInput:
+		return aoc.InputError(fmt.Errorf("invalid input file: %w", err))
+	case err != nil:
+		return err
+	}
+	return options.WriteAnswer(stdout, 2021, 2, part, position)
+}
@@ -0,0 +1,244 @@
+package day02_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/aoctest"
+	"github.com/dugword/advent-of-code-2021/years/2021/day02"
+)
+
+func TestLoadCommands(t *testing.T) {
+	t.Run("load commands from file", func(t *testing.T) {
+		want := []day02.Command{{
+			Direction: "forward",
+			Value:     1,
+		}, {
+			Direction: "down",
+			Value:     2,
+		}, {
+			Direction: "up",
+			Value:     3,
+		}}
+		got, err := day02.LoadCommands("./testdata/input")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got: %v, want: %v", got, want)
+		}
+	})
+	aoctest.LoaderRejects(t, day02.LoadCommands)
+	testCases := map[string]string{
+		"fail on unknown direction": "sideways 5\n",
+		"fail on negative value":    "down -1\n",
+		"fail on extra field":       "up 3 4\n",
+	}
+	for name, input := range testCases {
+		input := input
+		t.Run(name, func(t *testing.T) {
+			if _, got := day02.ReadCommands(strings.NewReader(input)); got == nil {
+				t.Error("did not fail as expected")
+			}
+		})
+	}
+}
+
+func TestCalculatePosition(t *testing.T) {
+	testCases := []struct {
+		input []day02.Command
+		want  int
+	}{
+		{
+			input: []day02.Command{{
+				Direction: "up",
+				Value:     1,
+			}, {
+				Direction: "down",
+				Value:     1,
+			}},
+			want: 0,
+		},
+		{
+			input: []day02.Command{{
+				Direction: "up",
+				Value:     2,
+			}, {
+				Direction: "forward",
+				Value:     2,
+			}},
+			want: -4,
+		},
+		{
+			input: []day02.Command{{
+				Direction: "up",
+				Value:     2,
+			}, {
+				Direction: "down",
+				Value:     4,
+			}, {
+				Direction: "forward",
+				Value:     5,
+			}},
+			want: 10,
+		},
+	}
+	for i, testCase := range testCases {
+		testName := fmt.Sprintf("test case %d", i)
+		t.Run(testName, func(t *testing.T) {
+			horizontal, depth := day02.CalculatePosition(testCase.input)
+			got := horizontal * depth
+			if got != testCase.want {
+				t.Errorf("got: %d, want: %d", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestCalculatePositionWithAim(t *testing.T) {
+	testCases := []struct {
+		input []day02.Command
+		want  int
+	}{
+		{
+			input: []day02.Command{{
+				Direction: "up",
+				Value:     1,
+			}, {
+				Direction: "down",
+				Value:     1,
+			}},
+			want: 0,
+		},
+		{
+			input: []day02.Command{{
+				Direction: "up",
+				Value:     2,
+			}, {
+				Direction: "forward",
+				Value:     2,
+			}},
+			want: -8,
+		},
+		{
+			input: []day02.Command{{
+				Direction: "up",
+				Value:     2,
+			}, {
+				Direction: "down",
+				Value:     4,
+			}, {
+				Direction: "forward",
+				Value:     5,
+			}},
+			want: 50,
+		},
+	}
+	for i, testCase := range testCases {
+		testName := fmt.Sprintf("test case %d", i)
+		t.Run(testName, func(t *testing.T) {
+			horizontal, depth := day02.CalculatePositionWithAim(testCase.input)
+			got := horizontal * depth
+			if got != testCase.want {
+				t.Errorf("got: %d, want: %d", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Run("run without failure", func(t *testing.T) {
+		got := aoctest.Run(t, day02.Run, "day_02", "-input", "./testdata/input")
+		aoctest.Golden(t, "./testdata/part_1.golden", got)
+	})
+	t.Run("run part 2 without failure", func(t *testing.T) {
+		got := aoctest.Run(t, day02.Run, "day_02", "-input", "./testdata/input", "-part-2")
+		aoctest.Golden(t, "./testdata/part_2.golden", got)
+	})
+	t.Run("log to stderr", func(t *testing.T) {
+		_, stderr := aoctest.RunStderr(t, day02.Run, "day_02", "-input", "./testdata/input", "-part-2", "-log-level", "trace", "-log-format", "json")
+		for _, want := range []string{`"msg":"read input"`, `"level":"TRACE","msg":"aim"`, `"msg":"final aim"`} {
+			if !strings.Contains(stderr, want) {
+				t.Errorf("got: %q, want to contain: %q", stderr, want)
+			}
+		}
+	})
+	t.Run("check the example before the input", func(t *testing.T) {
+		got := aoctest.Run(t, day02.Run, "day_02", "-input", "./testdata/input", "-check-example")
+		aoctest.Golden(t, "./testdata/part_1.golden", got)
+	})
+	t.Run("print usage on help", func(t *testing.T) {
+		got := aoctest.RunError(t, day02.Run, "day_02", "-h")
+		aoctest.AssertExitCode(t, got, aoc.ExitOK)
+	})
+	t.Run("fail on missing argument", func(t *testing.T) {
+		got := aoctest.RunError(t, day02.Run, "day_02")
+		aoctest.AssertError(t, got, "must provide an input file")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+	t.Run("fail on invalid argument", func(t *testing.T) {
+		got := aoctest.RunError(t, day02.Run, "day_02", "-invalid")
+		aoctest.AssertError(t, got, "flag provided but not defined: -invalid")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+	t.Run("fail on invalid input file", func(t *testing.T) {
+		got := aoctest.RunError(t, day02.Run, "day_02", "-input", "./testdata/invalid")
+		aoctest.AssertErrorPrefix(t, got, "invalid input file:")
+		aoctest.AssertExitCode(t, got, aoc.ExitInput)
+	})
+	t.Run("fail on timeout", func(t *testing.T) {
+		got := aoctest.RunError(t, day02.Run, "day_02", "-input", "./testdata/input", "-timeout", "1ns")
+		if !errors.Is(got, aoc.ErrTimeout) {
+			t.Errorf("got: %v, want: %v", got, aoc.ErrTimeout)
+		}
+		aoctest.AssertExitCode(t, got, aoc.ExitSolver)
+	})
+}
+
+func TestExample(t *testing.T) {
+	aoctest.Example(t, day02.Solver)
+}
+
+func TestCases(t *testing.T) {
+	aoctest.Cases(t, day02.Solver)
+}
+
+func FuzzReadCommands(f *testing.F) {
+	aoctest.AddSeedCorpus(f)
+	f.Fuzz(func(t *testing.T, input []byte) {
+		commands, err := day02.ReadCommands(bytes.NewReader(input))
+		if err != nil {
+			return
+		}
+		var buf bytes.Buffer
+		if err := day02.WriteCommands(&buf, commands); err != nil {
+			t.Fatal(err)
+		}
+		got, err := day02.ReadCommands(&buf)
+		if err != nil {
+			t.Fatalf("failed to read formatted commands: %v", err)
+		}
+		if !reflect.DeepEqual(got, commands) {
+			t.Errorf("got: %v, want: %v", got, commands)
+		}
+	})
+}
+
+func FuzzSolve(f *testing.F) {
+	aoctest.AddSeedCorpus(f)
+	f.Fuzz(func(t *testing.T, input []byte) {
+		_, readErr := day02.ReadCommands(bytes.NewReader(input))
+		for i, solve := range []aoc.SolveFunc{day02.SolvePart1, day02.SolvePart2} {
+			_, err := solve(context.Background(), bytes.NewReader(input))
+			if (err == nil) != (readErr == nil) {
+				t.Errorf("part %d got: %v, want the error of reading: %v", i+1, err, readErr)
+			}
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package day02
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"math/rand"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+)
+
+// CommandSequence generates commands that keep the submarine between the
+// surface and MaxDepth, ignoring aim.
+type CommandSequence struct {
+	Length   int
+	MaxValue int
+	MaxDepth int
+}
+
+// NewCommandSequence with parameters resembling a real puzzle input.
+func NewCommandSequence() *CommandSequence {
+	return &CommandSequence{
+		Length:   1000,
+		MaxValue: 9,
+		MaxDepth: 1000,
+	}
+}
+
+// Register the flags for each parameter.
+func (c *CommandSequence) Register(flags *flag.FlagSet) {
+	flags.IntVar(&c.Length, "n", c.Length, "number of commands")
+	flags.IntVar(&c.MaxValue, "max-value", c.MaxValue, "largest value of a single command")
+	flags.IntVar(&c.MaxDepth, "max-depth", c.MaxDepth, "deepest the submarine may go, ignoring aim")
+}
+
+// Commands generated from rng.
+func (c *CommandSequence) Commands(rng *rand.Rand) ([]Command, error) {
+	if c.Length < 0 {
+		return nil, errors.New("length must not be negative")
+	}
+	if c.MaxValue < 1 {
+		return nil, errors.New("max value must be positive")
+	}
+	if c.MaxDepth < 1 {
+		return nil, errors.New("max depth must be positive")
+	}
+	commands := make([]Command, c.Length)
+	depth := 0
+	for i := range commands {
+		value := 1 + rng.Intn(c.MaxValue)
+		direction := []string{"down", "forward", "up"}[rng.Intn(3)]
+		switch {
+		case direction == "up" && depth == 0:
+			direction = "down"
+		case direction == "down" && depth == c.MaxDepth:
+			direction = "up"
+		}
+		switch direction {
+		case "up":
+			value = min(value, depth)
+			depth -= value
+		case "down":
+			value = min(value, c.MaxDepth-depth)
+			depth += value
+		}
+		commands[i] = Command{
+			Direction: direction,
+			Value:     value,
+		}
+	}
+	return commands, nil
+}
+
+// Generate a commands file.
+func (c *CommandSequence) Generate(w io.Writer, rng *rand.Rand) (answers [2]int, err error) {
+	commands, err := c.Commands(rng)
+	if err != nil {
+		return answers, err
+	}
+	if err := WriteCommands(w, commands); err != nil {
+		return answers, err
+	}
+	return referenceAnswers(commands), nil
+}
+
+// referenceAnswers uses the part 1 depth as the part 2 aim, since both
+// change by the same amount for every up and down command.
+func referenceAnswers(commands []Command) (answers [2]int) {
+	horizontal, aim, depth := 0, 0, 0
+	for _, command := range commands {
+		switch command.Direction {
+		case "down":
+			aim += command.Value
+		case "up":
+			aim -= command.Value
+		default:
+			horizontal += command.Value
+			depth += aim * command.Value
+		}
+	}
+	return [2]int{horizontal * aim, horizontal * depth}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+var _ aoc.Generator = (*CommandSequence)(nil)
@@ -0,0 +1,80 @@
+package day02_test
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/years/2021/day02"
+)
+
+func TestCommandSequenceGenerate(t *testing.T) {
+	testCases := []*day02.CommandSequence{
+		day02.NewCommandSequence(),
+		{Length: 0, MaxValue: 1, MaxDepth: 1},
+		{Length: 200, MaxValue: 1, MaxDepth: 1},
+		{Length: 200, MaxValue: 100, MaxDepth: 5},
+	}
+	for i, generator := range testCases {
+		for seed := int64(0); seed < 10; seed++ {
+			var buf bytes.Buffer
+			answers, err := generator.Generate(&buf, rand.New(rand.NewSource(seed)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			commands, err := day02.ReadCommands(&buf)
+			if err != nil {
+				t.Fatalf("test case %d: generated invalid input: %v", i, err)
+			}
+			if len(commands) != generator.Length {
+				t.Errorf("test case %d: got %d commands, want %d", i, len(commands), generator.Length)
+			}
+			depth := 0
+			for _, command := range commands {
+				if command.Value < 0 || command.Value > generator.MaxValue {
+					t.Fatalf("test case %d: value %d out of range", i, command.Value)
+				}
+				switch command.Direction {
+				case "down":
+					depth += command.Value
+				case "up":
+					depth -= command.Value
+				}
+				if depth < 0 || depth > generator.MaxDepth {
+					t.Fatalf("test case %d: depth %d out of range", i, depth)
+				}
+			}
+			horizontal, depth := day02.CalculatePosition(commands)
+			horizontalWithAim, depthWithAim := day02.CalculatePositionWithAim(commands)
+			got := [2]int{horizontal * depth, horizontalWithAim * depthWithAim}
+			if got != answers {
+				t.Errorf("test case %d seed %d: got: %v, want: %v", i, seed, got, answers)
+			}
+		}
+	}
+}
+
+func TestCommandSequenceCommands(t *testing.T) {
+	t.Run("generate the same commands from the same seed", func(t *testing.T) {
+		generator := day02.NewCommandSequence()
+		a, _ := generator.Commands(rand.New(rand.NewSource(1)))
+		b, _ := generator.Commands(rand.New(rand.NewSource(1)))
+		if !reflect.DeepEqual(a, b) {
+			t.Error("commands differ for the same seed")
+		}
+	})
+	testCases := map[string]*day02.CommandSequence{
+		"fail on negative length":    {Length: -1, MaxValue: 1, MaxDepth: 1},
+		"fail on non-positive value": {Length: 1, MaxValue: 0, MaxDepth: 1},
+		"fail on non-positive depth": {Length: 1, MaxValue: 1, MaxDepth: 0},
+	}
+	for name, generator := range testCases {
+		generator := generator
+		t.Run(name, func(t *testing.T) {
+			if _, got := generator.Commands(rand.New(rand.NewSource(1))); got == nil {
+				t.Error("did not fail as expected")
+			}
+		})
+	}
+}
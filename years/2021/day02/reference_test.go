@@ -0,0 +1,54 @@
+package day02_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/difftest"
+	"github.com/dugword/advent-of-code-2021/years/2021/day02"
+)
+
+// naivePositionWithAim recomputes the aim from every earlier command each
+// time the submarine moves forward.
+func naivePositionWithAim(commands []day02.Command) [2]int {
+	horizontal, depth := 0, 0
+	for i, command := range commands {
+		if command.Direction != "forward" {
+			continue
+		}
+		aim := 0
+		for _, earlier := range commands[:i] {
+			switch earlier.Direction {
+			case "down":
+				aim += earlier.Value
+			case "up":
+				aim -= earlier.Value
+			}
+		}
+		horizontal += command.Value
+		depth += aim * command.Value
+	}
+	return [2]int{horizontal, depth}
+}
+
+func randomCommands(rng *rand.Rand) []day02.Command {
+	generator := &day02.CommandSequence{
+		Length:   rng.Intn(50),
+		MaxValue: 1 + rng.Intn(20),
+		MaxDepth: 1 + rng.Intn(100),
+	}
+	commands, err := generator.Commands(rng)
+	if err != nil {
+		panic(err)
+	}
+	return commands
+}
+
+func TestDifferentialCalculatePositionWithAim(t *testing.T) {
+	config := difftest.Config{Iterations: 5000}
+	candidate := func(commands []day02.Command) [2]int {
+		horizontal, depth := day02.CalculatePositionWithAim(commands)
+		return [2]int{horizontal, depth}
+	}
+	difftest.Check(t, config, randomCommands, difftest.ShrinkSlice[day02.Command], naivePositionWithAim, candidate)
+}
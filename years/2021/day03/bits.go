@@ -0,0 +1,70 @@
+package day03
+
+import (
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+// parallelThreshold is the number of diagnostics above which countBits
+// splits the counting across goroutines.
+const parallelThreshold = 1 << 16
+
+// countBits counts the one bits in each of the width columns of the
+// diagnostics, from the most significant column, in parallel for large
+// reports when there are several CPUs.
+func countBits(diagnostics []int, width int) []int {
+	workers := runtime.GOMAXPROCS(0)
+	if len(diagnostics) < parallelThreshold || workers == 1 {
+		return CountBits(diagnostics, width)
+	}
+	return CountBitsParallel(diagnostics, width, workers)
+}
+
+// CountBits counts the one bits in each of the width columns of the
+// diagnostics, from the most significant column. It transposes each block of
+// 64 diagnostics into a bitset per column and counts the bits set in each.
+func CountBits(diagnostics []int, width int) []int {
+	counts := make([]int, width)
+	// Columns past the 64 bits of a diagnostic are never set.
+	columns := min(width, 64)
+	for start := 0; start < len(diagnostics); start += 64 {
+		block := diagnostics[start:min(start+64, len(diagnostics))]
+		for i := 0; i < columns; i++ {
+			var column uint64
+			for j, diagnostic := range block {
+				column |= (uint64(diagnostic) >> i & 1) << j
+			}
+			counts[width-1-i] += bits.OnesCount64(column)
+		}
+	}
+	return counts
+}
+
+// CountBitsParallel counts like CountBits, splitting the diagnostics between
+// up to workers goroutines and adding up their counts.
+func CountBitsParallel(diagnostics []int, width, workers int) []int {
+	blocks := (len(diagnostics) + 63) / 64
+	workers = max(min(workers, blocks), 1)
+	// Each worker takes whole blocks of 64 diagnostics.
+	size := (blocks + workers - 1) / workers * 64
+	partial := make([][]int, workers)
+	var wg sync.WaitGroup
+	for w := range partial {
+		start := min(w*size, len(diagnostics))
+		end := min(start+size, len(diagnostics))
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			partial[w] = CountBits(diagnostics[start:end], width)
+		}(w)
+	}
+	wg.Wait()
+	counts := make([]int, width)
+	for _, p := range partial {
+		for i, count := range p {
+			counts[i] += count
+		}
+	}
+	return counts
+}
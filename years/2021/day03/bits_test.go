@@ -0,0 +1,85 @@
+package day03_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/years/2021/day03"
+)
+
+func TestCountBits(t *testing.T) {
+	testCases := []struct {
+		name        string
+		diagnostics []int
+		width       int
+		want        []int
+	}{
+		{name: "none", diagnostics: nil, width: 3, want: []int{0, 0, 0}},
+		{name: "columns", diagnostics: []int{0b100, 0b110, 0b111}, width: 3, want: []int{3, 2, 1}},
+		{name: "narrower than the diagnostics", diagnostics: []int{0b100, 0b011}, width: 2, want: []int{1, 1}},
+		{name: "negative", diagnostics: []int{-1}, width: 2, want: []int{1, 1}},
+		{name: "wider than 64 bits", diagnostics: []int{1}, width: 66, want: append(make([]int, 65), 1)},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := day03.CountBits(testCase.diagnostics, testCase.width); !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("got: %v, want: %v", got, testCase.want)
+			}
+			for _, workers := range []int{0, 1, 4} {
+				if got := day03.CountBitsParallel(testCase.diagnostics, testCase.width, workers); !reflect.DeepEqual(got, testCase.want) {
+					t.Errorf("%d workers: got: %v, want: %v", workers, got, testCase.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeHugeReport(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping huge report in short mode")
+	}
+	diagnostics := hugeReport(1 << 17)
+	gamma, epsilon := day03.DecodeReport(diagnostics)
+	if got, want := [2]int{gamma, epsilon}, naiveDecodeReport(diagnostics); got != want {
+		t.Errorf("got: %v, want: %v", got, want)
+	}
+}
+
+// hugeReport of n random 12 bit diagnostics, with more ones in the higher
+// columns.
+func hugeReport(n int) []int {
+	rng := rand.New(rand.NewSource(3))
+	diagnostics := make([]int, n)
+	for i := range diagnostics {
+		diagnostics[i] = rng.Intn(1<<12) | rng.Intn(1<<12)&0b111111000000
+	}
+	return diagnostics
+}
+
+func BenchmarkCountBits(b *testing.B) {
+	for _, n := range []int{1000, 1 << 20, 1 << 24} {
+		diagnostics := hugeReport(n)
+		benchmarks := []struct {
+			name  string
+			count func([]int, int) []int
+		}{
+			{name: "masks", count: naiveCountBits},
+			{name: "bit-sliced", count: day03.CountBits},
+			{name: "parallel", count: func(diagnostics []int, width int) []int {
+				return day03.CountBitsParallel(diagnostics, width, runtime.GOMAXPROCS(0))
+			}},
+		}
+		for _, benchmark := range benchmarks {
+			name := strings.Join([]string{benchmark.name, fmt.Sprint(n)}, "/")
+			b.Run(name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					benchmark.count(diagnostics, day03.Width)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package day03
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/bittrie"
+	"github.com/dugword/advent-of-code-2021/internal/inputs"
+	"github.com/dugword/advent-of-code-2021/internal/logging"
+)
+
+// Width of the diagnostics in the puzzle input, in bits.
+const Width = 12
+
+// example from the puzzle description.
+//
+//go:embed example
+var example embed.FS
+
+// Solver for day 3.
+var Solver = aoc.Solver{
+	Year:  2021,
+	Day:   3,
+	Part1: SolvePart1,
+	Part2: SolvePart2,
+	NewGenerator: func() aoc.Generator {
+		return NewReport()
+	},
+	Example: example,
+}
+
+func init() {
+	aoc.Register(Solver)
+}
+
+// LoadDiagnostics from a file.
+func LoadDiagnostics(filepath string) ([]int, error) {
+	file, err := inputs.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadDiagnostics(file)
+}
+
+// ReadDiagnostics from a reader.
+func ReadDiagnostics(r io.Reader) ([]int, error) {
+	diagnostics, _, err := readDiagnostics(context.Background(), r)
+	return diagnostics, err
+}
+
+// readDiagnostics from a reader, with their width in bits, which is the length
+// of the longest line.
+func readDiagnostics(ctx context.Context, r io.Reader) (diagnostics []int, width int, err error) {
+	defer logging.Phase(ctx, "read")()
+	diagnostics, width, err = inputs.Binary(ctx, r)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, diagnostic := range diagnostics {
+		if diagnostic < math.MinInt16 || diagnostic > math.MaxInt16 {
+			return nil, 0, fmt.Errorf("diagnostic %b does not fit in 16 bits", diagnostic)
+		}
+	}
+	return diagnostics, width, nil
+}
+
+// WriteDiagnostics to a writer in the format read by ReadDiagnostics.
+func WriteDiagnostics(w io.Writer, diagnostics []int) error {
+	for _, diagnostic := range diagnostics {
+		if _, err := fmt.Fprintf(w, "%012b\n", diagnostic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeReport from a slice of diagnostics of Width bits.
+func DecodeReport(diagnostics []int) (gamma, epsilon int) {
+	return DecodeReportWidth(diagnostics, Width)
+}
+
+// DecodeReportWidth from a slice of diagnostics of width bits.
+func DecodeReportWidth(diagnostics []int, width int) (gamma, epsilon int) {
+	d := 0
+	for _, count := range countBits(diagnostics, width) {
+		d <<= 1
+		if count > len(diagnostics)/2 {
+			d++
+		}
+	}
+	return d, (1<<width - 1) ^ d
+}
+
+// logBitCounts logs the one bits counted in each column at debug level.
+func logBitCounts(ctx context.Context, diagnostics []int, width int) {
+	logger := logging.FromContext(ctx)
+	if !logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	logger.DebugContext(ctx, "bit counts", "diagnostics", len(diagnostics), "width", width, "ones", countBits(diagnostics, width))
+}
+
+// Criterion chooses the bit to keep at a position, counted from the most
+// significant bit, from the number of remaining diagnostics with a one and a
+// zero there. It returns 1 to keep the ones and 0 to keep the zeros.
+type Criterion func(ones, zeros, position int) int
+
+// OxygenGeneratorCriterion keeps the most common bit, or the ones if both
+// are as common.
+func OxygenGeneratorCriterion(ones, zeros, position int) int {
+	if ones >= zeros {
+		return 1
+	}
+	return 0
+}
+
+// CO2ScrubberCriterion keeps the least common bit, or the zeros if both are
+// as common.
+func CO2ScrubberCriterion(ones, zeros, position int) int {
+	if ones < zeros {
+		return 1
+	}
+	return 0
+}
+
+// Rating from a slice of diagnostics of width bits, or 0 if there are none.
+// The diagnostics are filtered one position at a time, from the most
+// significant, by the bit chosen by criterion, until one is left. Positions
+// where every remaining diagnostic has the same bit are skipped.
+func Rating(diagnostics []int, width int, criterion Criterion) int {
+	return RatingTrie(NewTrie(width, diagnostics), criterion)
+}
+
+// RatingTrie rates the diagnostics in a trie like Rating, in time
+// proportional to its width, so that one trie can answer every rating.
+func RatingTrie(trie *bittrie.BitTrie, criterion Criterion) int {
+	rating, _ := trie.Walk(func(depth, zeros, ones int) int {
+		return criterion(ones, zeros, depth)
+	})
+	return rating
+}
+
+// GetOxygenGeneratorRating from a slice of diagnostics, or 0 if there are none.
+func GetOxygenGeneratorRating(bitLength int, diagnostics []int) int {
+	return Rating(diagnostics, bitLength, OxygenGeneratorCriterion)
+}
+
+// GetCO2ScrubberRating from a slice of diagnostics, or 0 if there are none.
+func GetCO2ScrubberRating(bitLength int, diagnostics []int) int {
+	return Rating(diagnostics, bitLength, CO2ScrubberCriterion)
+}
+
+// NewTrie of the low bitLength bits of the diagnostics, to rate with
+// RatingTrie.
+func NewTrie(bitLength int, diagnostics []int) *bittrie.BitTrie {
+	trie := bittrie.New(bitLength)
+	for _, diagnostic := range diagnostics {
+		trie.Insert(diagnostic)
+	}
+	return trie
+}
+
+// SolvePart1 calculates the power consumption from a diagnostics input.
+func SolvePart1(ctx context.Context, input io.Reader) (int, error) {
+	diagnostics, width, err := readDiagnostics(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	logBitCounts(ctx, diagnostics, width)
+	done := logging.Phase(ctx, "decode")
+	gamma, epsilon := DecodeReportWidth(diagnostics, width)
+	done()
+	aoc.Record(ctx, "gamma", gamma)
+	aoc.Record(ctx, "epsilon", epsilon)
+	return gamma * epsilon, nil
+}
+
+// SolvePart2 calculates the life support rating from a diagnostics input.
+func SolvePart2(ctx context.Context, input io.Reader) (int, error) {
+	diagnostics, width, err := readDiagnostics(ctx, input)
+	if err != nil {
+		return 0, err
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	logBitCounts(ctx, diagnostics, width)
+	done := logging.Phase(ctx, "rate")
+	trie := NewTrie(width, diagnostics)
+	oxygenGeneratorRating := RatingTrie(trie, OxygenGeneratorCriterion)
+	co2ScrubberRating := RatingTrie(trie, CO2ScrubberCriterion)
+	done()
+	aoc.Record(ctx, "oxygen_generator_rating", oxygenGeneratorRating)
+	aoc.Record(ctx, "co2_scrubber_rating", co2ScrubberRating)
+	return oxygenGeneratorRating * co2ScrubberRating, nil
+}
+
+// Run is an abstraction for main() that enables testing.
+func Run(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("day_01", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	inputFilepath := flags.String("input", "", "path to input file")
+	part2 := flags.Bool("part-2", false, "use part 2 logic")
+	radix := flags.Int("radix", 2, "radix of the diagnostics, or 0 to detect it from their digits")
+	var options aoc.Options
+	options.Register(flags)
+	if err := aoc.ParseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+	if *inputFilepath == "" {
+		return aoc.UsageError(errors.New("must provide an input file"))
+	}
+	if *radix != 0 && (*radix < 2 || *radix > 36) {
+		return aoc.UsageError(fmt.Errorf("radix must be 0 or between 2 and 36, got %d", *radix))
+	}
+	part, solve := 1, SolvePart1
+	if *part2 {
+		part, solve = 2, SolvePart2
+	}
+	if *radix != 2 {
+		if options.CheckExample {
+			return aoc.UsageError(errors.New("cannot check the binary example with -radix"))
+		}
+		solve = SolvePart1Radix(*radix)
+		if *part2 {
+			solve = SolvePart2Radix(*radix)
+		}
+	}
+	if options.CheckExample {
+		if err := aoc.CheckExample(context.Background(), Solver, part); err != nil {
+			return aoc.SolverError(err)
+		}
+	}
+	rating, err := options.Solve(solve, *inputFilepath, stderr)
+	switch {
+	case aoc.IsStopped(err):
+		return aoc.SolverError(fmt.Errorf("day 3 part %d: %w", part, err))
+	case aoc.ExitCode(err) == aoc.ExitInput:
+		return aoc.InputError(fmt.Errorf("invalid input file: %w", err))
+	case err != nil:
+		return err
+	}
+	return options.WriteAnswer(stdout, 2021, 3, part, rating)
+}
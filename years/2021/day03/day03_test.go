@@ -0,0 +1,332 @@
+package day03_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/aoctest"
+	"github.com/dugword/advent-of-code-2021/years/2021/day03"
+)
+
+func TestLoadDiagnostics(t *testing.T) {
+	t.Run("load diagnostics from file", func(t *testing.T) {
+		want := []int{
+			0b000111111001,
+			0b111011110110,
+			0b101111111000,
+		}
+		got, err := day03.LoadDiagnostics("./testdata/input")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got: %v, want: %v", got, want)
+		}
+	})
+	aoctest.LoaderRejects(t, day03.LoadDiagnostics)
+}
+
+func TestDecodeReport(t *testing.T) {
+	testCases := []struct {
+		input       []int
+		wantGamma   int
+		wantEpsilon int
+	}{
+		{
+			input: []int{
+				0b000000000001,
+				0b000000000010,
+				0b000000000011,
+			},
+			wantGamma:   0b000000000011,
+			wantEpsilon: 0b111111111100,
+		},
+		{
+			input: []int{
+				0b000000000000,
+				0b000000000010,
+				0b000000000001,
+			},
+			wantGamma:   0b000000000000,
+			wantEpsilon: 0b111111111111,
+		},
+		{
+			input: []int{
+				0b000000000001,
+				0b000000000010,
+				0b000000000010,
+			},
+			wantGamma:   0b000000000010,
+			wantEpsilon: 0b111111111101,
+		},
+	}
+	for i, testCase := range testCases {
+		testName := fmt.Sprintf("test case %d", i)
+		t.Run(testName, func(t *testing.T) {
+			gotGamma, gotEpsilon := day03.DecodeReport(testCase.input)
+			if gotGamma != testCase.wantGamma {
+				t.Errorf("\ngot gamma:  %12.12b\nwant gamma: %12.12b", gotGamma, testCase.wantGamma)
+			}
+			if gotEpsilon != testCase.wantEpsilon {
+				t.Errorf("\ngot epsilon:  %12.12b\nwant epsilon: %12.12b", gotEpsilon, testCase.wantEpsilon)
+			}
+		})
+	}
+}
+
+func TestDecodeReportWidth(t *testing.T) {
+	input := []int{0b00100, 0b11110, 0b10110, 0b10111, 0b10101, 0b01111, 0b00111, 0b11100, 0b10000, 0b11001, 0b00010, 0b01010}
+	gamma, epsilon := day03.DecodeReportWidth(input, 5)
+	if gamma != 0b10110 || epsilon != 0b01001 {
+		t.Errorf("got: %05b %05b, want: %05b %05b", gamma, epsilon, 0b10110, 0b01001)
+	}
+}
+
+func TestGetOxygenGeneratorRating(t *testing.T) {
+	testCases := []struct {
+		input []int
+		want  int
+	}{{
+		input: []int{
+			0b000000000001,
+			0b000000000010,
+			0b000000000011,
+		},
+		want: 0b000000000011,
+	}, {
+		input: []int{
+			0b000000000101,
+			0b000000000110,
+			0b000000000011,
+		},
+		want: 0b000000000110,
+	}, {
+		input: []int{
+			0b100000000111,
+			0b111111111000,
+			0b100000000000,
+		},
+		want: 0b100000000111,
+	}, {
+		input: []int{
+			0b000000000100,
+			0b000000011110,
+			0b000000010110,
+			0b000000010111,
+			0b000000010101,
+			0b000000001111,
+			0b000000000111,
+			0b000000011100,
+			0b000000010000,
+			0b000000011001,
+			0b000000000010,
+			0b000000001010,
+		},
+		want: 0b000000010111,
+	}}
+	for i, testCase := range testCases {
+		testName := fmt.Sprintf("test case %d", i)
+		t.Run(testName, func(t *testing.T) {
+			got := day03.GetOxygenGeneratorRating(12, testCase.input)
+			if !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("\ngot:  %12.12b\nwant: %12.12b", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestGetCO2ScrubberRating(t *testing.T) {
+	testCases := []struct {
+		input []int
+		want  int
+	}{{
+		input: []int{
+			0b000000000001,
+			0b000000000010,
+			0b000000000011,
+		},
+		want: 0b000000000001,
+	}, {
+		input: []int{
+			0b000000000101,
+			0b000000000110,
+			0b000000000011,
+		},
+		want: 0b000000000011,
+	}, {
+		input: []int{
+			0b100000000111,
+			0b111111111000,
+			0b100000000000,
+		},
+		want: 0b111111111000,
+	}, {
+		input: []int{
+			0b000000000100,
+			0b000000011110,
+			0b000000010110,
+			0b000000010111,
+			0b000000010101,
+			0b000000001111,
+			0b000000000111,
+			0b000000011100,
+			0b000000010000,
+			0b000000011001,
+			0b000000000010,
+			0b000000001010,
+		},
+		want: 0b000000001010,
+	}}
+	for i, testCase := range testCases {
+		testName := fmt.Sprintf("test case %d", i)
+		t.Run(testName, func(t *testing.T) {
+			got := day03.GetCO2ScrubberRating(12, testCase.input)
+			if !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("\ngot:  %12.12b\nwant: %12.12b", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestRating(t *testing.T) {
+	example := []int{0b00100, 0b11110, 0b10110, 0b10111, 0b10101, 0b01111, 0b00111, 0b11100, 0b10000, 0b11001, 0b00010, 0b01010}
+	testCases := []struct {
+		name      string
+		criterion day03.Criterion
+		want      int
+	}{
+		{name: "oxygen generator", criterion: day03.OxygenGeneratorCriterion, want: 0b10111},
+		{name: "CO2 scrubber", criterion: day03.CO2ScrubberCriterion, want: 0b01010},
+		{name: "zeros", criterion: func(ones, zeros, position int) int { return 0 }, want: 0b00010},
+		{name: "ones", criterion: func(ones, zeros, position int) int { return 1 }, want: 0b11110},
+		{name: "alternate", criterion: func(ones, zeros, position int) int { return position % 2 }, want: 0b01010},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := day03.Rating(example, 5, testCase.criterion); got != testCase.want {
+				t.Errorf("got: %05b, want: %05b", got, testCase.want)
+			}
+		})
+	}
+	t.Run("rate nothing", func(t *testing.T) {
+		if got := day03.Rating(nil, 5, day03.OxygenGeneratorCriterion); got != 0 {
+			t.Errorf("got: %v, want: %v", got, 0)
+		}
+	})
+	t.Run("rate one trie by every criterion", func(t *testing.T) {
+		trie := day03.NewTrie(5, example)
+		for _, testCase := range testCases {
+			if got := day03.RatingTrie(trie, testCase.criterion); got != testCase.want {
+				t.Errorf("%s got: %05b, want: %05b", testCase.name, got, testCase.want)
+			}
+		}
+	})
+	t.Run("skip positions with one bit", func(t *testing.T) {
+		var positions []int
+		criterion := func(ones, zeros, position int) int {
+			positions = append(positions, position)
+			return day03.OxygenGeneratorCriterion(ones, zeros, position)
+		}
+		day03.Rating([]int{0b100, 0b101}, 3, criterion)
+		if want := []int{2}; !reflect.DeepEqual(positions, want) {
+			t.Errorf("got: %v, want: %v", positions, want)
+		}
+	})
+}
+
+func TestRun(t *testing.T) {
+	t.Run("run without failure", func(t *testing.T) {
+		got := aoctest.Run(t, day03.Run, "day_03", "-input", "./testdata/input")
+		aoctest.Golden(t, "./testdata/part_1.golden", got)
+	})
+	t.Run("run part 2 without failure", func(t *testing.T) {
+		got := aoctest.Run(t, day03.Run, "day_03", "-input", "./testdata/input", "-part-2")
+		aoctest.Golden(t, "./testdata/part_2.golden", got)
+	})
+	t.Run("log to stderr", func(t *testing.T) {
+		_, stderr := aoctest.RunStderr(t, day03.Run, "day_03", "-input", "./testdata/input", "-log-level", "debug", "-log-format", "json")
+		for _, want := range []string{`"msg":"read input"`, `"msg":"bit counts"`, `"msg":"value","name":"gamma"`} {
+			if !strings.Contains(stderr, want) {
+				t.Errorf("got: %q, want to contain: %q", stderr, want)
+			}
+		}
+	})
+	t.Run("check the example before the input", func(t *testing.T) {
+		got := aoctest.Run(t, day03.Run, "day_03", "-input", "./testdata/input", "-check-example")
+		aoctest.Golden(t, "./testdata/part_1.golden", got)
+	})
+	t.Run("print usage on help", func(t *testing.T) {
+		got := aoctest.RunError(t, day03.Run, "day_03", "-h")
+		aoctest.AssertExitCode(t, got, aoc.ExitOK)
+	})
+	t.Run("fail on missing argument", func(t *testing.T) {
+		got := aoctest.RunError(t, day03.Run, "day_03")
+		aoctest.AssertError(t, got, "must provide an input file")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+	t.Run("fail on invalid argument", func(t *testing.T) {
+		got := aoctest.RunError(t, day03.Run, "day_03", "-invalid")
+		aoctest.AssertError(t, got, "flag provided but not defined: -invalid")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+	t.Run("fail on invalid input file", func(t *testing.T) {
+		got := aoctest.RunError(t, day03.Run, "day_03", "-input", "./testdata/invalid")
+		aoctest.AssertErrorPrefix(t, got, "invalid input file:")
+		aoctest.AssertExitCode(t, got, aoc.ExitInput)
+	})
+	t.Run("fail on timeout", func(t *testing.T) {
+		got := aoctest.RunError(t, day03.Run, "day_03", "-input", "./testdata/input", "-timeout", "1ns")
+		if !errors.Is(got, aoc.ErrTimeout) {
+			t.Errorf("got: %v, want: %v", got, aoc.ErrTimeout)
+		}
+		aoctest.AssertExitCode(t, got, aoc.ExitSolver)
+	})
+}
+
+func TestExample(t *testing.T) {
+	aoctest.Example(t, day03.Solver)
+}
+
+func TestCases(t *testing.T) {
+	aoctest.Cases(t, day03.Solver)
+}
+
+func FuzzReadDiagnostics(f *testing.F) {
+	aoctest.AddSeedCorpus(f)
+	f.Fuzz(func(t *testing.T, input []byte) {
+		diagnostics, err := day03.ReadDiagnostics(bytes.NewReader(input))
+		if err != nil {
+			return
+		}
+		var buf bytes.Buffer
+		if err := day03.WriteDiagnostics(&buf, diagnostics); err != nil {
+			t.Fatal(err)
+		}
+		got, err := day03.ReadDiagnostics(&buf)
+		if err != nil {
+			t.Fatalf("failed to read formatted diagnostics: %v", err)
+		}
+		if !reflect.DeepEqual(got, diagnostics) {
+			t.Errorf("got: %v, want: %v", got, diagnostics)
+		}
+	})
+}
+
+func FuzzSolve(f *testing.F) {
+	aoctest.AddSeedCorpus(f)
+	f.Fuzz(func(t *testing.T, input []byte) {
+		_, readErr := day03.ReadDiagnostics(bytes.NewReader(input))
+		for i, solve := range []aoc.SolveFunc{day03.SolvePart1, day03.SolvePart2} {
+			_, err := solve(context.Background(), bytes.NewReader(input))
+			if (err == nil) != (readErr == nil) {
+				t.Errorf("part %d got: %v, want the error of reading: %v", i+1, err, readErr)
+			}
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package day03
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+)
+
+// maxWidth of a diagnostic accepted by LoadDiagnostics.
+const maxWidth = 15
+
+// Report generates diagnostic reports whose bits are each set with
+// probability Bias. The solver takes the width from the longest diagnostic, so
+// the answers of every Width can be checked against it.
+type Report struct {
+	Length int
+	Width  int
+	Bias   float64
+}
+
+// NewReport with parameters resembling a real puzzle input.
+func NewReport() *Report {
+	return &Report{
+		Length: 1000,
+		Width:  12,
+		Bias:   0.5,
+	}
+}
+
+// Register the flags for each parameter.
+func (r *Report) Register(flags *flag.FlagSet) {
+	flags.IntVar(&r.Length, "n", r.Length, "number of diagnostics")
+	flags.IntVar(&r.Width, "width", r.Width, "number of bits in each diagnostic")
+	flags.Float64Var(&r.Bias, "bias", r.Bias, "probability of each bit being set")
+}
+
+// Diagnostics generated from rng, as strings of binary digits.
+func (r *Report) Diagnostics(rng *rand.Rand) ([]string, error) {
+	if r.Length < 0 {
+		return nil, errors.New("length must not be negative")
+	}
+	if r.Width < 1 || r.Width > maxWidth {
+		return nil, errors.New("width must be between 1 and 15")
+	}
+	if r.Bias < 0 || r.Bias > 1 {
+		return nil, errors.New("bias must be between 0 and 1")
+	}
+	diagnostics := make([]string, r.Length)
+	for i := range diagnostics {
+		var diagnostic strings.Builder
+		for j := 0; j < r.Width; j++ {
+			if rng.Float64() < r.Bias {
+				diagnostic.WriteByte('1')
+			} else {
+				diagnostic.WriteByte('0')
+			}
+		}
+		diagnostics[i] = diagnostic.String()
+	}
+	return diagnostics, nil
+}
+
+// Generate a diagnostics file.
+func (r *Report) Generate(w io.Writer, rng *rand.Rand) (answers [2]int, err error) {
+	diagnostics, err := r.Diagnostics(rng)
+	if err != nil {
+		return answers, err
+	}
+	for _, diagnostic := range diagnostics {
+		if _, err := io.WriteString(w, diagnostic+"\n"); err != nil {
+			return answers, err
+		}
+	}
+	return referenceAnswers(diagnostics, r.Width), nil
+}
+
+// referenceAnswers works on the diagnostics as text, one column at a time.
+func referenceAnswers(diagnostics []string, width int) (answers [2]int) {
+	var gamma, epsilon strings.Builder
+	for i := 0; i < width; i++ {
+		ones := countOnes(diagnostics, i)
+		if ones > len(diagnostics)-ones {
+			gamma.WriteByte('1')
+			epsilon.WriteByte('0')
+		} else {
+			gamma.WriteByte('0')
+			epsilon.WriteByte('1')
+		}
+	}
+	oxygen := referenceRating(diagnostics, width, func(ones, zeros int) byte {
+		if ones >= zeros {
+			return '1'
+		}
+		return '0'
+	})
+	co2 := referenceRating(diagnostics, width, func(ones, zeros int) byte {
+		if ones < zeros {
+			return '1'
+		}
+		return '0'
+	})
+	return [2]int{
+		parseBinary(gamma.String()) * parseBinary(epsilon.String()),
+		parseBinary(oxygen) * parseBinary(co2),
+	}
+}
+
+func referenceRating(diagnostics []string, width int, keep func(ones, zeros int) byte) string {
+	if len(diagnostics) == 0 {
+		return "0"
+	}
+	for i := 0; i < width && len(diagnostics) > 1; i++ {
+		ones := countOnes(diagnostics, i)
+		bit := keep(ones, len(diagnostics)-ones)
+		var kept []string
+		for _, diagnostic := range diagnostics {
+			if diagnostic[i] == bit {
+				kept = append(kept, diagnostic)
+			}
+		}
+		if len(kept) > 0 {
+			diagnostics = kept
+		}
+	}
+	return diagnostics[0]
+}
+
+func countOnes(diagnostics []string, i int) int {
+	ones := 0
+	for _, diagnostic := range diagnostics {
+		if diagnostic[i] == '1' {
+			ones++
+		}
+	}
+	return ones
+}
+
+func parseBinary(s string) int {
+	n, _ := strconv.ParseInt(s, 2, 64)
+	return int(n)
+}
+
+var _ aoc.Generator = (*Report)(nil)
@@ -0,0 +1,106 @@
+package day03_test
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/years/2021/day03"
+)
+
+func TestReportGenerate(t *testing.T) {
+	testCases := []*day03.Report{
+		day03.NewReport(),
+		{Length: 0, Width: 12, Bias: 0.5},
+		{Length: 1, Width: 12, Bias: 0.5},
+		{Length: 100, Width: 12, Bias: 0},
+		{Length: 100, Width: 12, Bias: 1},
+		{Length: 100, Width: 12, Bias: 0.9},
+		{Length: 2000, Width: 12, Bias: 0.3},
+	}
+	for i, generator := range testCases {
+		for seed := int64(0); seed < 10; seed++ {
+			var buf bytes.Buffer
+			answers, err := generator.Generate(&buf, rand.New(rand.NewSource(seed)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			diagnostics, err := day03.ReadDiagnostics(&buf)
+			if err != nil {
+				t.Fatalf("test case %d: generated invalid input: %v", i, err)
+			}
+			if len(diagnostics) != generator.Length {
+				t.Errorf("test case %d: got %d diagnostics, want %d", i, len(diagnostics), generator.Length)
+			}
+			gamma, epsilon := day03.DecodeReport(diagnostics)
+			oxygenGeneratorRating := day03.GetOxygenGeneratorRating(12, diagnostics)
+			co2ScrubberRating := day03.GetCO2ScrubberRating(12, diagnostics)
+			got := [2]int{gamma * epsilon, oxygenGeneratorRating * co2ScrubberRating}
+			if got != answers {
+				t.Errorf("test case %d seed %d: got: %v, want: %v", i, seed, got, answers)
+			}
+		}
+	}
+}
+
+func TestReportGenerateWidths(t *testing.T) {
+	for _, width := range []int{1, 5, 15} {
+		generator := &day03.Report{Length: 200, Width: width, Bias: 0.5}
+		for seed := int64(0); seed < 10; seed++ {
+			var buf bytes.Buffer
+			answers, err := generator.Generate(&buf, rand.New(rand.NewSource(seed)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got [2]int
+			for part, solve := range []aoc.SolveFunc{day03.SolvePart1, day03.SolvePart2} {
+				if got[part], err = solve(context.Background(), bytes.NewReader(buf.Bytes())); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got != answers {
+				t.Errorf("width %d seed %d: got: %v, want: %v", width, seed, got, answers)
+			}
+		}
+	}
+}
+
+func TestReportDiagnostics(t *testing.T) {
+	t.Run("generate diagnostics of the chosen width", func(t *testing.T) {
+		generator := &day03.Report{Length: 10, Width: 5, Bias: 0.5}
+		diagnostics, err := generator.Diagnostics(rand.New(rand.NewSource(1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, diagnostic := range diagnostics {
+			if len(diagnostic) != 5 {
+				t.Errorf("got: %q, want 5 bits", diagnostic)
+			}
+		}
+	})
+	t.Run("generate the same diagnostics from the same seed", func(t *testing.T) {
+		generator := day03.NewReport()
+		a, _ := generator.Diagnostics(rand.New(rand.NewSource(1)))
+		b, _ := generator.Diagnostics(rand.New(rand.NewSource(1)))
+		if !reflect.DeepEqual(a, b) {
+			t.Error("diagnostics differ for the same seed")
+		}
+	})
+	testCases := map[string]*day03.Report{
+		"fail on negative length": {Length: -1, Width: 12, Bias: 0.5},
+		"fail on zero width":      {Length: 1, Width: 0, Bias: 0.5},
+		"fail on excessive width": {Length: 1, Width: 16, Bias: 0.5},
+		"fail on invalid bias":    {Length: 1, Width: 12, Bias: 1.5},
+	}
+	for name, generator := range testCases {
+		generator := generator
+		t.Run(name, func(t *testing.T) {
+			if _, got := generator.Diagnostics(rand.New(rand.NewSource(1))); got == nil {
+				t.Error("did not fail as expected")
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package day03
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/inputs"
+	"github.com/dugword/advent-of-code-2021/internal/logging"
+)
+
+// radixes detected from the digits of a report, from smallest to largest.
+var radixes = []int{2, 8, 10, 16, 36}
+
+// LoadDiagnosticsRadix from a file of diagnostics written in radix, or in the
+// radix detected from their digits if radix is 0.
+func LoadDiagnosticsRadix(filepath string, radix int) ([]int, error) {
+	file, err := inputs.Open(filepath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadDiagnosticsRadix(file, radix)
+}
+
+// ReadDiagnosticsRadix from a reader of diagnostics written in radix, or in
+// the radix detected from their digits if radix is 0.
+func ReadDiagnosticsRadix(r io.Reader, radix int) ([]int, error) {
+	diagnostics, _, _, err := readDiagnosticsRadix(context.Background(), r, radix)
+	return diagnostics, err
+}
+
+// readDiagnosticsRadix from a reader, with their width in digits and the
+// radix they are written in.
+func readDiagnosticsRadix(ctx context.Context, r io.Reader, radix int) (diagnostics []int, width, detected int, err error) {
+	defer logging.Phase(ctx, "read")()
+	lines, err := inputs.Lines(ctx, r)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if radix == 0 {
+		if radix, err = DetectRadix(lines); err != nil {
+			return nil, 0, 0, err
+		}
+		logging.FromContext(ctx).DebugContext(ctx, "detected radix", "radix", radix)
+	}
+	if radix < 2 || radix > 36 {
+		return nil, 0, 0, fmt.Errorf("radix must be between 2 and 36, got %d", radix)
+	}
+	diagnostics = make([]int, 0, len(lines))
+	for _, line := range lines {
+		diagnostic, err := strconv.ParseUint(line, radix, 63)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		diagnostics = append(diagnostics, int(diagnostic))
+		if len(line) > width {
+			width = len(line)
+		}
+	}
+	if err := CheckWidth(width, radix); err != nil {
+		return nil, 0, 0, err
+	}
+	return diagnostics, width, radix, nil
+}
+
+// CheckWidth fails unless every diagnostic of width digits in radix, and so
+// every gamma, epsilon and place of a digit, fits in 63 bits.
+func CheckWidth(width, radix int) error {
+	const limit = uint64(1) << 63
+	values := uint64(1)
+	for i := 0; i < width; i++ {
+		if values > limit/uint64(radix) {
+			return fmt.Errorf("width of %d digits does not fit in 63 bits in radix %d", width, radix)
+		}
+		values *= uint64(radix)
+	}
+	return nil
+}
+
+// DetectRadix of diagnostics as the smallest of 2, 8, 10, 16 and 36 that has
+// every digit used by the lines.
+func DetectRadix(lines []string) (int, error) {
+	largest := 0
+	for _, line := range lines {
+		for _, c := range line {
+			d, ok := digit(c)
+			if !ok {
+				return 0, fmt.Errorf("invalid digit %q in %q", c, line)
+			}
+			largest = max(largest, d)
+		}
+	}
+	for _, radix := range radixes {
+		if largest < radix {
+			return radix, nil
+		}
+	}
+	panic("unreachable")
+}
+
+func digit(c rune) (int, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return int(c - '0'), true
+	case 'a' <= c && c <= 'z':
+		return int(c-'a') + 10, true
+	case 'A' <= c && c <= 'Z':
+		return int(c-'A') + 10, true
+	}
+	return 0, false
+}
+
+// DecodeReportRadix from a slice of diagnostics of width digits in radix,
+// where the width passes CheckWidth. Gamma has the most common digit of each
+// position, preferring the smaller of tied digits, and epsilon the least
+// common, preferring the larger, so that in radix 2 they match
+// DecodeReportWidth.
+func DecodeReportRadix(diagnostics []int, width, radix int) (gamma, epsilon int) {
+	for _, counts := range countDigits(diagnostics, width, radix) {
+		most, least := 0, radix-1
+		for d, count := range counts {
+			if count > counts[most] {
+				most = d
+			}
+			if count <= counts[least] {
+				least = d
+			}
+		}
+		gamma = gamma*radix + most
+		epsilon = epsilon*radix + least
+	}
+	return gamma, epsilon
+}
+
+// countDigits counts the diagnostics with each digit in each of the width
+// positions, from the most significant position.
+func countDigits(diagnostics []int, width, radix int) [][]int {
+	counts := make([][]int, width)
+	for i := range counts {
+		counts[i] = make([]int, radix)
+	}
+	for _, diagnostic := range diagnostics {
+		for i := width - 1; i >= 0; i-- {
+			counts[i][diagnostic%radix]++
+			diagnostic /= radix
+		}
+	}
+	return counts
+}
+
+// GetOxygenGeneratorRatingRadix from a slice of diagnostics of width digits
+// in radix, where the width passes CheckWidth, keeping those with the most
+// common digit of each position and preferring the larger of tied digits, or 0
+// if there are none.
+func GetOxygenGeneratorRatingRadix(width, radix int, diagnostics []int) int {
+	return rateRadix(width, radix, diagnostics, func(count, best int) bool {
+		return count >= best
+	})
+}
+
+// GetCO2ScrubberRatingRadix from a slice of diagnostics of width digits in
+// radix, where the width passes CheckWidth, keeping those with the least
+// common digit of each position and preferring the smaller of tied digits, or
+// 0 if there are none.
+func GetCO2ScrubberRatingRadix(width, radix int, diagnostics []int) int {
+	return rateRadix(width, radix, diagnostics, func(count, best int) bool {
+		return count < best
+	})
+}
+
+// rateRadix filters the diagnostics one position at a time, from the most
+// significant, keeping those with the digit whose count is better than the
+// counts of the smaller digits. Digits that no diagnostic has are ignored.
+func rateRadix(width, radix int, diagnostics []int, better func(count, best int) bool) int {
+	if len(diagnostics) == 0 {
+		return 0
+	}
+	place := 1
+	for i := 1; i < width; i++ {
+		place *= radix
+	}
+	for ; place > 0 && len(diagnostics) > 1; place /= radix {
+		groups := make([][]int, radix)
+		for _, diagnostic := range diagnostics {
+			d := diagnostic / place % radix
+			groups[d] = append(groups[d], diagnostic)
+		}
+		var kept []int
+		for _, group := range groups {
+			if len(group) > 0 && (kept == nil || better(len(group), len(kept))) {
+				kept = group
+			}
+		}
+		diagnostics = kept
+	}
+	return diagnostics[0]
+}
+
+// SolvePart1Radix calculates the power consumption from diagnostics written
+// in radix, or in the radix detected from their digits if radix is 0.
+func SolvePart1Radix(radix int) aoc.SolveFunc {
+	return func(ctx context.Context, input io.Reader) (int, error) {
+		diagnostics, width, radix, err := readDiagnosticsRadix(ctx, input, radix)
+		if err != nil {
+			return 0, err
+		}
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		done := logging.Phase(ctx, "decode")
+		gamma, epsilon := DecodeReportRadix(diagnostics, width, radix)
+		done()
+		aoc.Record(ctx, "gamma", gamma)
+		aoc.Record(ctx, "epsilon", epsilon)
+		return gamma * epsilon, nil
+	}
+}
+
+// SolvePart2Radix calculates the life support rating from diagnostics written
+// in radix, or in the radix detected from their digits if radix is 0.
+func SolvePart2Radix(radix int) aoc.SolveFunc {
+	return func(ctx context.Context, input io.Reader) (int, error) {
+		diagnostics, width, radix, err := readDiagnosticsRadix(ctx, input, radix)
+		if err != nil {
+			return 0, err
+		}
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		done := logging.Phase(ctx, "rate")
+		oxygenGeneratorRating := GetOxygenGeneratorRatingRadix(width, radix, diagnostics)
+		co2ScrubberRating := GetCO2ScrubberRatingRadix(width, radix, diagnostics)
+		done()
+		aoc.Record(ctx, "oxygen_generator_rating", oxygenGeneratorRating)
+		aoc.Record(ctx, "co2_scrubber_rating", co2ScrubberRating)
+		return oxygenGeneratorRating * co2ScrubberRating, nil
+	}
+}
@@ -0,0 +1,231 @@
+package day03_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	"github.com/dugword/advent-of-code-2021/internal/aoctest"
+	"github.com/dugword/advent-of-code-2021/years/2021/day03"
+)
+
+func TestDetectRadix(t *testing.T) {
+	testCases := []struct {
+		name  string
+		lines []string
+		want  int
+	}{
+		{name: "binary", lines: []string{"00100", "11110"}, want: 2},
+		{name: "octal", lines: []string{"0017", "0005"}, want: 8},
+		{name: "decimal", lines: []string{"19", "08"}, want: 10},
+		{name: "hex", lines: []string{"1a", "1F"}, want: 16},
+		{name: "base 36", lines: []string{"zz", "0g"}, want: 36},
+		{name: "empty", lines: nil, want: 2},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := day03.DetectRadix(testCase.lines)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != testCase.want {
+				t.Errorf("got: %v, want: %v", got, testCase.want)
+			}
+		})
+	}
+	t.Run("fail on invalid digit", func(t *testing.T) {
+		if _, got := day03.DetectRadix([]string{"1a", "1-"}); got == nil {
+			t.Error("did not fail as expected")
+		}
+	})
+}
+
+func TestLoadDiagnosticsRadix(t *testing.T) {
+	testCases := []struct {
+		name  string
+		radix int
+		want  []int
+	}{
+		{name: "hex", radix: 16, want: []int{0x1a, 0x1b, 0x2b}},
+		{name: "detect hex", radix: 0, want: []int{0x1a, 0x1b, 0x2b}},
+		{name: "base 36", radix: 36, want: []int{46, 47, 83}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			got, err := day03.LoadDiagnosticsRadix("./testdata/hex", testCase.radix)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("got: %v, want: %v", got, testCase.want)
+			}
+		})
+	}
+	for _, radix := range []int{1, 10, 37} {
+		if _, got := day03.LoadDiagnosticsRadix("./testdata/hex", radix); got == nil {
+			t.Errorf("radix %d: did not fail as expected", radix)
+		}
+	}
+	aoctest.LoaderRejects(t, func(filepath string) ([]int, error) {
+		return day03.LoadDiagnosticsRadix(filepath, 2)
+	})
+}
+
+func TestCheckWidth(t *testing.T) {
+	testCases := []struct {
+		width int
+		radix int
+		valid bool
+	}{
+		{width: 63, radix: 2, valid: true},
+		{width: 64, radix: 2, valid: false},
+		{width: 15, radix: 16, valid: true},
+		{width: 16, radix: 16, valid: false},
+		{width: 17, radix: 16, valid: false},
+		{width: 12, radix: 36, valid: true},
+		{width: 13, radix: 36, valid: false},
+	}
+	for _, testCase := range testCases {
+		t.Run(fmt.Sprintf("%d digits in radix %d", testCase.width, testCase.radix), func(t *testing.T) {
+			if got := day03.CheckWidth(testCase.width, testCase.radix); (got == nil) != testCase.valid {
+				t.Errorf("got: %v, want valid: %v", got, testCase.valid)
+			}
+		})
+	}
+	t.Run("fail on wide diagnostics", func(t *testing.T) {
+		input := "0000000000000001a\n0000000000000001b\n"
+		_, got := day03.ReadDiagnosticsRadix(strings.NewReader(input), 16)
+		if want := "width of 17 digits does not fit in 63 bits in radix 16"; got == nil || got.Error() != want {
+			t.Errorf("got: %v, want: %v", got, want)
+		}
+	})
+}
+
+func TestRadix(t *testing.T) {
+	hex := []int{0x1a, 0x1b, 0x2b}
+	t.Run("decode the most and least common digits", func(t *testing.T) {
+		gamma, epsilon := day03.DecodeReportRadix(hex, 2, 16)
+		if gamma != 0x1b || epsilon != 0xff {
+			t.Errorf("got: %x %x, want: %x %x", gamma, epsilon, 0x1b, 0xff)
+		}
+	})
+	t.Run("rate the oxygen generator", func(t *testing.T) {
+		if got := day03.GetOxygenGeneratorRatingRadix(2, 16, hex); got != 0x1b {
+			t.Errorf("got: %x, want: %x", got, 0x1b)
+		}
+	})
+	t.Run("rate the CO2 scrubber", func(t *testing.T) {
+		if got := day03.GetCO2ScrubberRatingRadix(2, 16, hex); got != 0x2b {
+			t.Errorf("got: %x, want: %x", got, 0x2b)
+		}
+	})
+	t.Run("rate nothing", func(t *testing.T) {
+		if got := day03.GetOxygenGeneratorRatingRadix(2, 16, nil); got != 0 {
+			t.Errorf("got: %v, want: %v", got, 0)
+		}
+	})
+}
+
+func TestRadixMatchesBinary(t *testing.T) {
+	testCases := []*day03.Report{
+		day03.NewReport(),
+		{Length: 1, Width: 12, Bias: 0.5},
+		{Length: 12, Width: 5, Bias: 0.5},
+		{Length: 100, Width: 12, Bias: 0},
+		{Length: 100, Width: 12, Bias: 1},
+		{Length: 2000, Width: 15, Bias: 0.3},
+	}
+	for i, generator := range testCases {
+		for seed := int64(0); seed < 10; seed++ {
+			var buf bytes.Buffer
+			if _, err := generator.Generate(&buf, rand.New(rand.NewSource(seed))); err != nil {
+				t.Fatal(err)
+			}
+			diagnostics, err := day03.ReadDiagnostics(&buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gamma, epsilon := day03.DecodeReportWidth(diagnostics, generator.Width)
+			want := [4]int{
+				gamma,
+				epsilon,
+				day03.GetOxygenGeneratorRating(generator.Width, diagnostics),
+				day03.GetCO2ScrubberRating(generator.Width, diagnostics),
+			}
+			gamma, epsilon = day03.DecodeReportRadix(diagnostics, generator.Width, 2)
+			got := [4]int{
+				gamma,
+				epsilon,
+				day03.GetOxygenGeneratorRatingRadix(generator.Width, 2, diagnostics),
+				day03.GetCO2ScrubberRatingRadix(generator.Width, 2, diagnostics),
+			}
+			if got != want {
+				t.Errorf("test case %d seed %d: got: %v, want: %v", i, seed, got, want)
+			}
+		}
+	}
+}
+
+func TestRunRadix(t *testing.T) {
+	for _, radix := range []string{"16", "0"} {
+		t.Run("run radix "+radix, func(t *testing.T) {
+			got := aoctest.Run(t, day03.Run, "day_03", "-input", "./testdata/hex", "-radix", radix)
+			aoctest.Golden(t, "./testdata/hex_part_1.golden", got)
+			got = aoctest.Run(t, day03.Run, "day_03", "-input", "./testdata/hex", "-radix", radix, "-part-2")
+			aoctest.Golden(t, "./testdata/hex_part_2.golden", got)
+		})
+	}
+	t.Run("run binary in radix 0", func(t *testing.T) {
+		got := aoctest.Run(t, day03.Run, "day_03", "-input", "./testdata/input", "-radix", "0")
+		aoctest.Golden(t, "./testdata/part_1.golden", got)
+	})
+	t.Run("log the detected radix", func(t *testing.T) {
+		_, stderr := aoctest.RunStderr(t, day03.Run, "day_03", "-input", "./testdata/hex", "-radix", "0", "-log-level", "debug")
+		if want := "detected radix"; !strings.Contains(stderr, want) {
+			t.Errorf("got: %q, want to contain: %q", stderr, want)
+		}
+	})
+	for _, radix := range []string{"1", "37", "-2"} {
+		t.Run("fail on radix "+radix, func(t *testing.T) {
+			got := aoctest.RunError(t, day03.Run, "day_03", "-input", "./testdata/hex", "-radix", radix)
+			aoctest.AssertError(t, got, "radix must be 0 or between 2 and 36, got "+radix)
+			aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+		})
+	}
+	t.Run("fail on hex input in binary", func(t *testing.T) {
+		got := aoctest.RunError(t, day03.Run, "day_03", "-input", "./testdata/hex")
+		aoctest.AssertErrorPrefix(t, got, "invalid input file:")
+		aoctest.AssertExitCode(t, got, aoc.ExitInput)
+	})
+	t.Run("fail on checking the example with a radix", func(t *testing.T) {
+		got := aoctest.RunError(t, day03.Run, "day_03", "-input", "./testdata/hex", "-radix", "16", "-check-example")
+		aoctest.AssertError(t, got, "cannot check the binary example with -radix")
+		aoctest.AssertExitCode(t, got, aoc.ExitUsage)
+	})
+}
+
+func FuzzReadDiagnosticsRadix(f *testing.F) {
+	aoctest.AddSeedCorpus(f)
+	f.Add([]byte("1A\n1B\n2B\n"))
+	f.Fuzz(func(t *testing.T, input []byte) {
+		diagnostics, err := day03.ReadDiagnosticsRadix(bytes.NewReader(input), 0)
+		if err != nil {
+			return
+		}
+		for _, diagnostic := range diagnostics {
+			if diagnostic < 0 {
+				t.Fatalf("got negative diagnostic: %v", diagnostic)
+			}
+		}
+		for _, solve := range []aoc.SolveFunc{day03.SolvePart1Radix(0), day03.SolvePart2Radix(0)} {
+			if _, err := solve(context.Background(), bytes.NewReader(input)); err != nil {
+				t.Errorf("failed to solve diagnostics that were read: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package day03_test
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/difftest"
+	"github.com/dugword/advent-of-code-2021/years/2021/day03"
+)
+
+// naiveDecodeReport formats every diagnostic as text and counts the ones in
+// each column.
+func naiveDecodeReport(diagnostics []int) [2]int {
+	gamma, epsilon := "", ""
+	for column := 0; column < 12; column++ {
+		ones, zeros := 0, 0
+		for _, diagnostic := range diagnostics {
+			if fmt.Sprintf("%012b", diagnostic)[column] == '1' {
+				ones++
+			} else {
+				zeros++
+			}
+		}
+		if ones > zeros {
+			gamma, epsilon = gamma+"1", epsilon+"0"
+		} else {
+			gamma, epsilon = gamma+"0", epsilon+"1"
+		}
+	}
+	return [2]int{parseBinary(gamma), parseBinary(epsilon)}
+}
+
+// naiveCountBits masks each column of every diagnostic in turn.
+func naiveCountBits(diagnostics []int, width int) []int {
+	counts := make([]int, width)
+	for _, diagnostic := range diagnostics {
+		for i := range counts {
+			if diagnostic&(1<<i) != 0 {
+				counts[len(counts)-1-i]++
+			}
+		}
+	}
+	return counts
+}
+
+// naiveRating filters the diagnostics as text, one column at a time, keeping
+// those whose bit in the column is the one chosen by keep.
+func naiveRating(diagnostics []int, keep func(ones, zeros int) byte) int {
+	if len(diagnostics) == 0 {
+		return 0
+	}
+	var remaining []string
+	for _, diagnostic := range diagnostics {
+		remaining = append(remaining, fmt.Sprintf("%012b", diagnostic))
+	}
+	for column := 0; column < 12 && len(remaining) > 1; column++ {
+		ones, zeros := 0, 0
+		for _, diagnostic := range remaining {
+			if diagnostic[column] == '1' {
+				ones++
+			} else {
+				zeros++
+			}
+		}
+		bit := keep(ones, zeros)
+		if ones == 0 || zeros == 0 {
+			continue
+		}
+		var kept []string
+		for _, diagnostic := range remaining {
+			if diagnostic[column] == bit {
+				kept = append(kept, diagnostic)
+			}
+		}
+		remaining = kept
+	}
+	return parseBinary(remaining[0])
+}
+
+func naiveOxygenGeneratorRating(diagnostics []int) int {
+	return naiveRating(diagnostics, func(ones, zeros int) byte {
+		if ones >= zeros {
+			return '1'
+		}
+		return '0'
+	})
+}
+
+func naiveCO2ScrubberRating(diagnostics []int) int {
+	return naiveRating(diagnostics, func(ones, zeros int) byte {
+		if ones < zeros {
+			return '1'
+		}
+		return '0'
+	})
+}
+
+func parseBinary(s string) int {
+	n, err := strconv.ParseInt(s, 2, 64)
+	if err != nil {
+		panic(err)
+	}
+	return int(n)
+}
+
+func randomDiagnostics(rng *rand.Rand) []int {
+	generator := &day03.Report{
+		Length: rng.Intn(50),
+		Width:  12,
+		Bias:   rng.Float64(),
+	}
+	report, err := generator.Diagnostics(rng)
+	if err != nil {
+		panic(err)
+	}
+	diagnostics := make([]int, len(report))
+	for i, diagnostic := range report {
+		diagnostics[i] = parseBinary(diagnostic)
+	}
+	return diagnostics
+}
+
+func TestDifferentialDecodeReport(t *testing.T) {
+	config := difftest.Config{Iterations: 5000}
+	candidate := func(diagnostics []int) [2]int {
+		gamma, epsilon := day03.DecodeReport(diagnostics)
+		return [2]int{gamma, epsilon}
+	}
+	difftest.Check(t, config, randomDiagnostics, difftest.ShrinkSlice[int], naiveDecodeReport, candidate)
+}
+
+func TestDifferentialCountBits(t *testing.T) {
+	config := difftest.Config{Iterations: 2000}
+	generate := func(rng *rand.Rand) []int {
+		diagnostics := make([]int, rng.Intn(300))
+		for i := range diagnostics {
+			diagnostics[i] = int(rng.Uint64())
+		}
+		return diagnostics
+	}
+	reference := func(diagnostics []int) string {
+		return fmt.Sprint(naiveCountBits(diagnostics, 64))
+	}
+	t.Run("count bits", func(t *testing.T) {
+		candidate := func(diagnostics []int) string {
+			return fmt.Sprint(day03.CountBits(diagnostics, 64))
+		}
+		difftest.Check(t, config, generate, difftest.ShrinkSlice[int], reference, candidate)
+	})
+	t.Run("count bits in parallel", func(t *testing.T) {
+		candidate := func(diagnostics []int) string {
+			return fmt.Sprint(day03.CountBitsParallel(diagnostics, 64, 3))
+		}
+		difftest.Check(t, config, generate, difftest.ShrinkSlice[int], reference, candidate)
+	})
+}
+
+func TestDifferentialGetOxygenGeneratorRating(t *testing.T) {
+	config := difftest.Config{Iterations: 5000}
+	candidate := func(diagnostics []int) int {
+		return day03.GetOxygenGeneratorRating(12, diagnostics)
+	}
+	difftest.Check(t, config, randomDiagnostics, difftest.ShrinkSlice[int], naiveOxygenGeneratorRating, candidate)
+}
+
+func TestDifferentialGetCO2ScrubberRating(t *testing.T) {
+	config := difftest.Config{Iterations: 5000}
+	candidate := func(diagnostics []int) int {
+		return day03.GetCO2ScrubberRating(12, diagnostics)
+	}
+	difftest.Check(t, config, randomDiagnostics, difftest.ShrinkSlice[int], naiveCO2ScrubberRating, candidate)
+}
@@ -0,0 +1,8 @@
+// Package years registers the solver for every day of every year with the
+// runner.
+package years
+
+import (
+	// Each year registers the solvers of its days when imported.
+	_ "github.com/dugword/advent-of-code-2021/years/2021"
+)
@@ -0,0 +1,23 @@
+package years_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dugword/advent-of-code-2021/internal/aoc"
+	_ "github.com/dugword/advent-of-code-2021/years"
+)
+
+func TestExamples(t *testing.T) {
+	for _, solver := range aoc.Solvers() {
+		solver := solver
+		t.Run(fmt.Sprintf("%d/day%02d", solver.Year, solver.Day), func(t *testing.T) {
+			for part := 1; part <= 2; part++ {
+				if err := aoc.CheckExample(context.Background(), solver, part); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}